@@ -4,68 +4,87 @@ import (
 	"vsc_nft_mgmt/sdk"
 )
 
-// =====================
-// Market Administration
-// =====================
+// ====================
+// Contract Admin
+// ====================
+//
+// The admin is stored in state under "adm". Until the first handover is
+// accepted, the compile-time contractOwner acts as admin, so existing
+// deployments keep working without an init call. Handover is two-step:
+// the admin proposes a successor (admin_transfer) who must accept it
+// (admin_accept), which protects against typos in the new address.
 
-const marketKey = "mc"
+const (
+	adminKey        = "adm"
+	pendingAdminKey = "adm_pending"
+)
 
-// AddMarketContract registers (an additional) marketplace contract address.
-// Only the contract owner may call this.
+// TransferAdmin proposes a new admin. A later proposal replaces a pending one.
+// Payload: "<newAdminAddress>"
+// Only the current admin may call this. An adminProposed event is emitted.
 //
-//go:wasmexport add_market
-func AddMarketContract(addr *string) *string {
+//go:wasmexport admin_transfer
+func TransferAdmin(addr *string) *string {
 	if addr == nil || *addr == "" {
-		sdk.Abort("market address required")
+		sdk.Abort("admin address required")
 	}
+	admin := loadAdmin()
 	sender := sdk.GetEnvKey("msg.sender")
-	if sender == nil || *sender != contractOwner {
-		sdk.Abort("only contract owner can set market")
+	if sender == nil || *sender != admin {
+		sdk.Abort("only admin can transfer admin")
 	}
-
-	existing := GetMarketContractsCSV(nil) // load current market list
-	if existing != nil && *existing != "" {
-		// if we already have at least one market contract
-		// prevent duplicate
-		if containsInCSV(*existing, *addr) {
-			return nil
-		}
-		newVal := *existing + "|" + *addr // append new contract to list
-		sdk.StateSetObject(marketKey, newVal)
-	} else {
-		// if we do not have any market contract yet
-		sdk.StateSetObject(marketKey, *addr)
+	if *addr == admin {
+		sdk.Abort("already admin")
+	}
+	if !isValidAccount(*addr) {
+		sdk.Abort("invalid admin address")
 	}
+
+	sdk.StateSetObject(pendingAdminKey, *addr)
+	emitAdmin("adminProposed", admin, *addr)
 	return nil
 }
 
-// RemoveMarketContract removes a marketplace contract address from the supported contracts list.
-// Only the contract owner may call this.
+// AcceptAdmin completes a handover started by admin_transfer.
+// Only the proposed admin may call this. An adminChanged event is emitted.
 //
-
-//go:wasmexport remove_market
-func RemoveMarketContract(addr *string) *string {
-	if addr == nil || *addr == "" {
-		sdk.Abort("market address required")
+//go:wasmexport admin_accept
+func AcceptAdmin(_ *string) *string {
+	pending := sdk.StateGetObject(pendingAdminKey)
+	if pending == nil || *pending == "" {
+		sdk.Abort("no pending admin")
 	}
 	sender := sdk.GetEnvKey("msg.sender")
-	if sender == nil || *sender != contractOwner {
-		sdk.Abort("only contract owner can remove market")
+	if sender == nil || *sender != *pending {
+		sdk.Abort("only pending admin can accept")
 	}
 
-	existing := GetMarketContractsCSV(nil)
-	if existing == nil || *existing == "" {
-		sdk.Abort("no marketplaces found")
-	}
-
-	newCSV := removeFromCSV(*existing, *addr)
-	sdk.StateSetObject(marketKey, newCSV)
+	previous := loadAdmin()
+	sdk.StateSetObject(adminKey, *pending)
+	sdk.StateDeleteObject(pendingAdminKey)
+	emitAdmin("adminChanged", previous, *sender)
 	return nil
 }
 
-// GetMarketContractsCSV retrieves the currently configured market contracts
+// GetAdmin returns the current admin and the pending successor (if any).
 //
-//go:wasmexport get_markets
-func GetMarketContractsCSV(_ *string) *string {
-	return sdk.StateGetObject(marketKey)
+// Returns: "<admin>|<pendingAdmin>" (pendingAdmin empty if no handover is open)
+//
+//go:wasmexport admin_get
+func GetAdmin(_ *string) *string {
+	admin := loadAdmin()
+	pending := ""
+	if ptr := sdk.StateGetObject(pendingAdminKey); ptr != nil {
+		pending = *ptr
+	}
+	s := admin + "|" + pending
+	return &s
+}
+
+// loadAdmin returns the stored admin, falling back to contractOwner.
+func loadAdmin() string {
+	if ptr := sdk.StateGetObject(adminKey); ptr != nil && *ptr != "" {
+		return *ptr
+	}
+	return contractOwner
 }
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"vsc_nft_mgmt/sdk"
+)
+
+// =========================
+// PER-EDITION NFT APPROVALS
+// =========================
+//
+// The owner of an NFT (or of a single edition) can approve exactly one extra
+// address to move it, e.g. an escrow contract or a friend. The approval lives
+// under approvalKey(nftID, edition) and is cleared on every transfer and burn.
+//
+// Additionally, an owner can register operators that may move and burn *all*
+// of the owner's NFTs, optionally limited to a single collection.
+
+// Approve grants an address the right to transfer one NFT or edition.
+// Payload format: "<nftID>|<editionIndex>|<approvedAddress>"
+// - If editionIndex is empty, it defaults to 0.
+// - A new approval replaces the previous one.
+// Only the current owner (or one of its operators) may approve. An approval event is emitted.
+//
+//go:wasmexport nft_approve
+func Approve(payload *string) *string {
+	requireNotPaused(pauseApprove)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	var ed uint32
+	if edStr := parts[1]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	approved := parts[2]
+	if approved == "" {
+		sdk.Abort("approved address required")
+	}
+	if !isValidAccount(approved) {
+		sdk.Abort("invalid approved address")
+	}
+
+	owner, effectiveEd, edTotal := requireTokenOwner(id, ed, "only owner can approve")
+	if approved == owner {
+		sdk.Abort("cannot approve current owner")
+	}
+
+	sdk.StateSetObject(approvalKey(id, effectiveEd), approved)
+	emitApproval(id, editionRef(effectiveEd, edTotal), owner, approved)
+	return nil
+}
+
+// RevokeApproval removes the approval of an NFT or edition.
+// Payload formats:
+//
+//	"<nftID>"           → unique NFT
+//	"<nftID>|<edition>" → specific edition
+//
+// Only the current owner (or one of its operators) may revoke.
+// An approval event with an empty "ap" is emitted.
+//
+//go:wasmexport nft_revoke
+func RevokeApproval(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	id, ed, _ := parseNFTRef(*payload)
+
+	owner, effectiveEd, edTotal := requireTokenOwner(id, ed, "only owner can revoke")
+	key := approvalKey(id, effectiveEd)
+	if ptr := sdk.StateGetObject(key); ptr == nil || *ptr == "" {
+		sdk.Abort("no approval set")
+	}
+
+	sdk.StateDeleteObject(key)
+	emitApproval(id, editionRef(effectiveEd, edTotal), owner, "")
+	return nil
+}
+
+// GetApproved returns the address approved for an NFT or edition.
+//
+// Payload formats:
+//
+//	"<nftID>"
+//	"<nftID>|<editionIndex>"
+//
+// Returns the approved address or an empty string if there is none.
+//
+//go:wasmexport nft_getApproved
+func GetApproved(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	id, ed, hasEdition := parseNFTRef(*payload)
+
+	edTotal := *loadNFTEditionCount(id)
+	if edTotal > 1 {
+		if !hasEdition {
+			sdk.Abort("edition required for multi-edition NFT")
+		}
+		if ed >= edTotal {
+			sdk.Abort("edition index out of range")
+		}
+	} else {
+		ed = 0
+	}
+
+	ptr := sdk.StateGetObject(approvalKey(id, ed))
+	if ptr == nil {
+		empty := ""
+		return &empty
+	}
+	return ptr
+}
+
+// ==================
+// Operator Approvals
+// ==================
+
+// SetOperator grants or revokes an operator for all NFTs of the caller.
+// Payload format: "<operator>|<approved>|<owner>_<collection>"
+// - approved is "true" to grant and "false" to revoke.
+// - The collection field is optional. If set, the operator is limited to
+// NFTs held in that collection, which must belong to the caller.
+// An operator event is emitted.
+//
+//go:wasmexport nft_setOperator
+func SetOperator(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	operator := parts[0]
+	approved := parts[1] == "true"
+	scope := parts[2]
+	if operator == "" {
+		sdk.Abort("operator address required")
+	}
+	if !approved && parts[1] != "false" {
+		sdk.Abort("approved must be true or false")
+	}
+	if approved {
+		requireNotPaused(pauseApprove) // revoking stays possible while paused
+		if !isValidAccount(operator) {
+			sdk.Abort("invalid operator address")
+		}
+	}
+
+	owner := *sdk.GetEnvKey("msg.caller")
+	if operator == owner {
+		sdk.Abort("cannot set self as operator")
+	}
+
+	key := operatorKey(owner, operator)
+	if scope != "" {
+		loadCollection(scope) // make sure the collection exists
+		if collectionOwner(scope) != owner {
+			sdk.Abort("collection not owned by caller")
+		}
+		key = operatorKey(scope, operator)
+	}
+
+	if approved {
+		// collection approvals record the granting owner, so they lapse
+		// once the collection is transferred (see isOperator)
+		sdk.StateSetObject(key, owner)
+	} else {
+		if ptr := sdk.StateGetObject(key); ptr == nil || *ptr == "" {
+			sdk.Abort("operator not set")
+		}
+		sdk.StateDeleteObject(key)
+	}
+	emitOperator(owner, operator, scope, approved)
+	return nil
+}
+
+// IsOperatorApproved checks whether an operator may act for an owner.
+//
+// Payload formats:
+//
+//	"<owner>|<operator>"              → owner-wide approval only
+//	"<owner>_<collection>|<operator>" → owner-wide or collection approval
+//
+// Returns: "true" or "false"
+//
+//go:wasmexport nft_isOperator
+func IsOperatorApproved(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	scope, operator := split2Str(*payload)
+	owner := scope
+	if indexByte(scope, '_') != -1 {
+		owner = collectionOwner(scope)
+	} else {
+		scope = ""
+	}
+
+	if isOperator(owner, scope, &operator) {
+		t := "true"
+		return &t
+	}
+	f := "false"
+	return &f
+}
+
+// ==================
+// Approval Internals
+// ==================
+
+// requireTokenOwner resolves the current owner of an NFT or edition and aborts
+// with msg unless msg.caller is that owner or one of its operators.
+// Returns (owner, effectiveEdition, editionTotal).
+func requireTokenOwner(nftID uint64, ed uint32, msg string) (string, uint32, uint32) {
+	ownerCol, effectiveEd, edTotal := loadTokenOwnerCollection(nftID, ed)
+	owner := collectionOwner(ownerCol)
+	caller := sdk.GetEnvKey("msg.caller")
+	if caller == nil || (*caller != owner && !isOperator(owner, ownerCol, caller)) {
+		sdk.Abort(msg)
+	}
+	return owner, effectiveEd, edTotal
+}
+
+// isApproved returns true if caller holds the approval for the given edition.
+func isApproved(nftID uint64, editionIndex uint32, caller *string) bool {
+	if caller == nil {
+		return false
+	}
+	ptr := sdk.StateGetObject(approvalKey(nftID, editionIndex))
+	return ptr != nil && *ptr != "" && *ptr == *caller
+}
+
+// isOperator returns true if caller is an operator of owner, either owner-wide
+// or for ownerCol (pass an empty ownerCol to check only owner-wide approvals).
+func isOperator(owner string, ownerCol string, caller *string) bool {
+	if caller == nil || *caller == "" {
+		return false
+	}
+	if ptr := sdk.StateGetObject(operatorKey(owner, *caller)); ptr != nil && *ptr != "" {
+		return true
+	}
+	if ownerCol == "" {
+		return false
+	}
+	ptr := sdk.StateGetObject(operatorKey(ownerCol, *caller))
+	return ptr != nil && *ptr == owner
+}
+
+// clearApproval drops any approval of the given edition (no-op if none is set).
+func clearApproval(nftID uint64, editionIndex uint32) {
+	key := approvalKey(nftID, editionIndex)
+	if ptr := sdk.StateGetObject(key); ptr != nil && *ptr != "" {
+		sdk.StateDeleteObject(key)
+	}
+}
+
+// editionRef returns a pointer to the edition for multi-edition NFTs and nil
+// for unique NFTs, matching the optional "ed" attribute of events.
+func editionRef(editionIndex uint32, edTotal uint32) *uint32 {
+	if edTotal > 1 {
+		return &editionIndex
+	}
+	return nil
+}
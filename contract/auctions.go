@@ -0,0 +1,258 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// =================
+// ENGLISH AUCTIONS
+// =================
+//
+// An owner can auction an NFT or a single edition for HIVE or HBD until a
+// given block height. Bids are escrowed in the contract (sdk.HiveDraw) and
+// the previous highest bidder is refunded right away (sdk.HiveTransfer).
+// After the end height anyone can settle: the winner receives the token and
+// the seller the highest bid minus royalties (see payoutSale). Without any bid
+// the auction simply ends.
+//
+// While an auction is open the token is locked: it cannot be transferred,
+// burned or listed.
+//
+// An auction is stored under auctionKey(nftID, edition) as
+// "<seller>|<reserve>|<asset>|<endHeight>|<bidder>|<bid>|<bidderCollection>"
+// (bidder fields empty until the first bid).
+
+type Auction struct {
+	Seller    string
+	Reserve   uint64
+	Asset     sdk.Asset
+	EndHeight uint64
+	Bidder    string
+	Bid       uint64
+	BidderCol string // "<owner>_<collection>" the token is delivered to
+}
+
+// StartAuction opens an auction for an NFT or edition.
+// Payload format: "<nftID>|<editionIndex>|<reservePrice>|<asset>|<endBlockHeight>"
+// - editionIndex may be empty for unique NFTs (defaults to 0)
+// - asset is "hive" or "hbd"; the first bid must be at least the reserve price
+// - endBlockHeight must be in the future and at most maxAuctionBlocks ahead
+// Only the current owner may start an auction; an existing listing is dropped.
+// An auction event is emitted.
+//
+//go:wasmexport auction_start
+func StartAuction(payload *string) *string {
+	requireNotPaused(pauseTrade)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 5)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	var ed uint32
+	if edStr := parts[1]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	reserve := parsePrice(parts[2])
+	asset := parseSaleAsset(parts[3])
+	end := mustParseUint64(parts[4])
+	height := currentBlockHeight()
+	if end <= height {
+		sdk.Abort("end height must be in the future")
+	}
+	if end-height > maxAuctionBlocks {
+		sdk.Abort("auction too long")
+	}
+
+	ownerCol, effectiveEd, edTotal := loadTokenOwnerCollection(id, ed)
+	requireNotInAuction(id, effectiveEd)
+	seller := collectionOwner(ownerCol)
+	caller := sdk.GetEnvKey("msg.caller")
+	if caller == nil || *caller != seller {
+		sdk.Abort("only owner can start auction")
+	}
+	requireUnbound(id, seller)
+
+	a := Auction{Seller: seller, Reserve: reserve, Asset: asset, EndHeight: end}
+	clearListing(id, effectiveEd)
+	saveAuction(id, effectiveEd, a)
+	emitAuction("auction", id, editionRef(effectiveEd, edTotal), a)
+	return nil
+}
+
+// BidAuction places a bid; the amount is drawn from the caller via intents.
+// Payload format: "<nftID>|<editionIndex>|<amount>|<owner>_<collection>"
+// - amount must reach the reserve price and exceed the current highest bid
+// - the collection receives the token if the bid wins and must belong to the bidder
+// The previous highest bidder is refunded. A bid event is emitted.
+//
+//go:wasmexport auction_bid
+func BidAuction(payload *string) *string {
+	requireNotPaused(pauseTrade)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 4)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	var ed uint32
+	if edStr := parts[1]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	amount := parsePrice(parts[2])
+	target := parts[3]
+
+	edTotal := *loadNFTEditionCount(id)
+	if edTotal <= 1 {
+		ed = 0
+	}
+	a := loadAuction(id, ed)
+	if a == nil {
+		sdk.Abort("auction not found")
+	}
+	if currentBlockHeight() >= a.EndHeight {
+		sdk.Abort("auction has ended")
+	}
+	bidder := *sdk.GetEnvKey("msg.caller")
+	if bidder == a.Seller {
+		sdk.Abort("seller cannot bid")
+	}
+	if amount < a.Reserve {
+		sdk.Abort("bid below reserve price")
+	}
+	if a.Bidder != "" && amount <= a.Bid {
+		sdk.Abort("bid too low")
+	}
+	loadCollection(target) // make sure the collection exists
+	if collectionOwner(target) != bidder {
+		sdk.Abort("target collection not owned by bidder")
+	}
+
+	sdk.HiveDraw(int64(amount), a.Asset)
+	if a.Bidder != "" {
+		sdk.HiveTransfer(sdk.Address(a.Bidder), int64(a.Bid), a.Asset)
+	}
+	a.Bidder, a.Bid, a.BidderCol = bidder, amount, target
+	saveAuction(id, ed, *a)
+	emitBid(id, editionRef(ed, edTotal), bidder, amount, a.Asset)
+	return nil
+}
+
+// SettleAuction closes an auction once its end height is reached.
+// Payload formats: "<nftID>" or "<nftID>|<edition>"
+// Callable by anyone. With a winning bid the seller and royalty recipients are
+// paid and the token moves to the winner's collection (sale and transfer
+// events); an auctionEnd event is emitted in any case. The winning bid is
+// binding: the token goes to the collection named in the bid even if that
+// collection has changed owner since.
+//
+//go:wasmexport auction_settle
+func SettleAuction(payload *string) *string {
+	requireNotPaused(pauseTrade | pauseTransfer)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	id, ed, _ := parseNFTRef(*payload)
+	ownerCol, effectiveEd, edTotal := loadTokenOwnerCollection(id, ed)
+	a := loadAuction(id, effectiveEd)
+	if a == nil {
+		sdk.Abort("auction not found")
+	}
+	if currentBlockHeight() < a.EndHeight {
+		sdk.Abort("auction still running")
+	}
+
+	sdk.StateDeleteObject(auctionKey(id, effectiveEd))
+	edRef := editionRef(effectiveEd, edTotal)
+	emitAuction("auctionEnd", id, edRef, *a)
+	if a.Bidder == "" {
+		return nil
+	}
+	royalty := payoutSale(id, a.Bid, a.Asset, a.Seller)
+	moveToken(id, effectiveEd, edTotal, ownerCol, a.BidderCol)
+	emitSale(id, edRef, a.Seller, a.Bidder, a.Bid, a.Asset, royalty)
+	return nil
+}
+
+// GetAuction returns the open auction of an NFT or edition.
+//
+// Payload formats: "<nftID>" or "<nftID>|<edition>"
+//
+// Returns: "<seller>|<reserve>|<asset>|<endHeight>|<bidder>|<bid>"
+// (bidder and bid empty without bids) or an empty string if there is none.
+//
+//go:wasmexport auction_get
+func GetAuction(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	id, ed, _ := parseNFTRef(*payload)
+	out := ""
+	if a := loadAuction(id, ed); a != nil {
+		s := auctionToStr(*a)
+		out = s[:lastIndexByte(s, '|')] // bidder collection is internal
+	}
+	return &out
+}
+
+// ================
+// Internal Helpers
+// ================
+
+// requireNotInAuction aborts if the token is locked by an open auction.
+func requireNotInAuction(nftID uint64, editionIndex uint32) {
+	if ptr := sdk.StateGetObject(auctionKey(nftID, editionIndex)); ptr != nil && *ptr != "" {
+		sdk.Abort("nft is locked in auction")
+	}
+}
+
+func currentBlockHeight() uint64 {
+	ptr := sdk.GetEnvKey("block.height")
+	if ptr == nil || *ptr == "" {
+		sdk.Abort("block height unavailable")
+	}
+	return mustParseUint64(*ptr)
+}
+
+func loadAuction(nftID uint64, editionIndex uint32) *Auction {
+	ptr := sdk.StateGetObject(auctionKey(nftID, editionIndex))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	parts := splitFixedPipe(*ptr, 7)
+	a := &Auction{
+		Seller:    parts[0],
+		Reserve:   mustParseUint64(parts[1]),
+		Asset:     sdk.Asset(parts[2]),
+		EndHeight: mustParseUint64(parts[3]),
+		Bidder:    parts[4],
+		BidderCol: parts[6],
+	}
+	if parts[5] != "" {
+		a.Bid = mustParseUint64(parts[5])
+	}
+	return a
+}
+
+func saveAuction(nftID uint64, editionIndex uint32, a Auction) {
+	sdk.StateSetObject(auctionKey(nftID, editionIndex), auctionToStr(a))
+}
+
+func auctionToStr(a Auction) string {
+	b := make([]byte, 0, len(a.Seller)+len(a.Bidder)+len(a.BidderCol)+64)
+	b = append(b, a.Seller...)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, a.Reserve, 10)
+	b = append(b, '|')
+	b = append(b, a.Asset...)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, a.EndHeight, 10)
+	b = append(b, '|')
+	b = append(b, a.Bidder...)
+	b = append(b, '|')
+	if a.Bidder != "" {
+		b = strconv.AppendUint(b, a.Bid, 10)
+	}
+	b = append(b, '|')
+	b = append(b, a.BidderCol...)
+	return string(b)
+}
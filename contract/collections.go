@@ -10,16 +10,35 @@ import (
 // ============================
 
 // CreateCollection creates a new NFT collection owned by the caller.
-// Payload format: "<name>|<desc>|<metadata>"
-// Name and description are validated for length. If either is invalid,
-// the function aborts. This function is non-reversible by design as collections themselves are immutable.
+// Payload formats:
+//
+//	"<name>|<desc>|<metadata>"
+//	"<name>|<desc>|<maxNFTs>|<maxEditions>|<metadata>"
+//
+// Exactly two '|' select the short layout; any other payload is read with the
+// supply caps (empty cap = unlimited), so metadata containing '|' needs the
+// long layout. Name and description are validated for length. If either is
+// invalid, the function aborts. A default royalty can be set right after
+// creation (col_setRoyalty); col_setMaxSupply lowers the caps later on.
+// Collections cannot be deleted; description and metadata can be changed via
+// col_update until the collection is locked.
 //
 //go:wasmexport col_create
 func CreateCollection(payload *string) *string {
+	requireNotPaused(pauseCollection)
 	if payload == nil || *payload == "" {
 		sdk.Abort("empty payload")
 	}
-	parts := splitFixedPipe(*payload, 3)
+	var parts []string
+	var supply CollectionSupply
+	if countByte(*payload, '|') == 2 {
+		parts = splitFixedPipe(*payload, 3)
+	} else {
+		capped := splitFixedPipe(*payload, 5)
+		supply.MaxNFTs = parseSupplyCap(capped[2])
+		supply.MaxEditions = parseSupplyCap(capped[3])
+		parts = []string{capped[0], capped[1], capped[4]}
+	}
 	name := parts[0]
 	desc := parts[1]
 	meta := parts[2]
@@ -47,14 +66,563 @@ func CreateCollection(payload *string) *string {
 	b = append(b, '|')
 	b = append(b, meta...)
 
-	idxKey := colIndexKey(creator, strconv.FormatUint(colNumber, 10))
-	sdk.StateSetObject(idxKey, string(b))   // store collection
+	colIdx := strconv.FormatUint(colNumber, 10)
+	sdk.StateSetObject(colIndexKey(creator, colIdx), string(b)) // store collection
+	if supply.MaxNFTs > 0 || supply.MaxEditions > 0 {
+		saveCollectionSupply(creator+"_"+colIdx, supply)
+	}
 	updateUserCollectionCount(id, creator)  // increment collection counter for user
 	EmitCollectionCreatedEvent(id, creator) // emit event for indexers
 
 	return nil
 }
 
+// ===============================
+// Collection Ownership Transfer
+// ===============================
+//
+// Collections keep their "<owner>_<collection>" ID forever (it is embedded in
+// every NFT owner record). A transfer writes an owner override under
+// "co_<owner>_<collection>" that collectionOwner() resolves everywhere the
+// owner of a collection matters: minting, transfers, burns, approvals,
+// collection updates and default royalties.
+
+// TransferCollection hands a collection to a new owner.
+// Payload format: "<owner>_<collection>|<newOwner>"
+// - Only the current collection owner may call this.
+// - NFTs and editions in the collection move with it: holdings and owned-edition
+// indexes are re-credited to the new owner, approvals and listings are cleared.
+// Aborts if a token of the collection is in an auction.
+// - Delegated minters are cleared (col_addMinter must be called again by the new
+// owner); the new owner can mint right away.
+// - Default royalties without an explicit recipient pay the new owner from now on.
+// A collectionTransfer event is emitted.
+//
+//go:wasmexport col_transfer
+func TransferCollection(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	ownerCol, newOwner := split2Str(*payload)
+	if !isValidAccount(newOwner) {
+		sdk.Abort("invalid new owner")
+	}
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+
+	current := collectionOwner(ownerCol)
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || *sender != current {
+		sdk.Abort("only collection owner can transfer")
+	}
+	if newOwner == current {
+		sdk.Abort("already collection owner")
+	}
+	moveCollectionTokens(ownerCol, current, newOwner)
+
+	embedded, _ := splitOwnerCollection(ownerCol)
+	if newOwner == embedded {
+		sdk.StateDeleteObject(colOwnerKey(ownerCol)) // back to the original owner
+	} else {
+		sdk.StateSetObject(colOwnerKey(ownerCol), newOwner)
+	}
+	sdk.StateDeleteObject(colMintersKey(ownerCol))
+
+	emitCollectionTransfer(ownerCol, current, newOwner)
+	return nil
+}
+
+// collectionOwner returns the current owner of a collection: the override
+// written by col_transfer, or the owner embedded in "<owner>_<collection>".
+func collectionOwner(ownerCollection string) string {
+	if ptr := sdk.StateGetObject(colOwnerKey(ownerCollection)); ptr != nil && *ptr != "" {
+		return *ptr
+	}
+	owner, _ := splitOwnerCollection(ownerCollection)
+	return owner
+}
+
+// moveCollectionTokens re-credits every live token of a collection from one
+// owner to another: holdings, owned-edition ranges, approvals and listings.
+// Editions are looked up through the old owner's owned-edition ranges, so the
+// work per NFT is bounded by the editions that owner holds rather than by the
+// edition count of the NFT.
+func moveCollectionTokens(ownerCol string, from, to string) {
+	set := collectionMembers(ownerCol)
+	members, _ := set.head()
+	for slot := uint64(0); slot < members; slot++ {
+		nftID := set.member(slot)
+		edTotal := *loadNFTEditionCount(nftID)
+		if edTotal <= 1 {
+			requireNotInAuction(nftID, 0)
+			clearApproval(nftID, 0)
+			clearListing(nftID, 0)
+		} else {
+			base := *loadNFTOwnerCollection(nftID)
+			owned := loadOwnedRanges(nftID, from)
+			var ranges []uint32
+			for i := 0; i+1 < len(owned); i += 2 {
+				for ed := owned[i]; ed < owned[i+1]; ed++ {
+					holder := base
+					if eo := loadEditionOverride(nftID, ed); eo != nil {
+						if eo.Burned {
+							continue
+						}
+						holder = eo.OwnerCollection
+					}
+					if holder != ownerCol {
+						continue // held in another collection of the old owner
+					}
+					requireNotInAuction(nftID, ed)
+					clearApproval(nftID, ed)
+					clearListing(nftID, ed)
+					ranges = mergeRange(ranges, ed, ed+1)
+				}
+			}
+			for i := 0; i+1 < len(ranges); i += 2 {
+				moveEditionRange(nftID, ranges[i], ranges[i+1], from, to)
+			}
+		}
+		moveHolding(nftID, set.tokens(nftID), from, to)
+	}
+}
+
+// colOwnerKey returns "co_<owner>_<collection>" holding the owner override.
+func colOwnerKey(ownerCollection string) string {
+	return "co_" + ownerCollection
+}
+
+// =================
+// Collection Update
+// =================
+
+// UpdateCollection rewrites the description and metadata of a collection.
+// Payload format: "<owner>_<collection>|<desc>|<metadata>"
+// The original tx id and name are kept. Locked collections (col_lock) can
+// never be updated again.
+// Only the collection owner may call this. A collectionUpdated event is emitted.
+//
+//go:wasmexport col_update
+func UpdateCollection(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	ownerCol := parts[0]
+	desc := parts[1]
+	meta := parts[2]
+	if len(desc) > maxDescLength {
+		sdk.Abort("description too long")
+	}
+
+	current := *loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	owner, col := splitOwnerCollection(ownerCol)
+	requireUnlockedCollectionOwner(ownerCol, "only collection owner can update")
+
+	tx, name, _, _ := parse4(current)
+	b := make([]byte, 0, len(tx)+1+len(name)+1+len(desc)+1+len(meta))
+	b = append(b, tx...)
+	b = append(b, '|')
+	b = append(b, name...)
+	b = append(b, '|')
+	b = append(b, desc...)
+	b = append(b, '|')
+	b = append(b, meta...)
+	sdk.StateSetObject(colIndexKey(owner, col), string(b))
+
+	emitCollectionUpdated(ownerCol, false)
+	return nil
+}
+
+// LockCollection permanently locks the description and metadata of a collection.
+// Payload: "<owner>_<collection>"
+// Locking is irreversible; col_update aborts afterwards.
+// Only the collection owner may call this. A collectionUpdated event with
+// "lk":true is emitted.
+//
+//go:wasmexport col_lock
+func LockCollection(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	ownerCol := *payload
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	requireUnlockedCollectionOwner(ownerCol, "only collection owner can lock")
+
+	sdk.StateSetObject(colLockKey(ownerCol), "1")
+	emitCollectionUpdated(ownerCol, true)
+	return nil
+}
+
+// requireUnlockedCollectionOwner aborts unless msg.sender owns the collection
+// and the collection is not locked yet.
+func requireUnlockedCollectionOwner(ownerCol string, msg string) {
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || *sender != collectionOwner(ownerCol) {
+		sdk.Abort(msg)
+	}
+	if isCollectionLocked(ownerCol) {
+		sdk.Abort("collection is locked")
+	}
+}
+
+// isCollectionLocked returns true once col_lock locked the collection.
+func isCollectionLocked(ownerCollection string) bool {
+	ptr := sdk.StateGetObject(colLockKey(ownerCollection))
+	return ptr != nil && *ptr == "1"
+}
+
+// colLockKey returns "cl_<owner>_<collection>" holding the permanent lock flag.
+func colLockKey(ownerCollection string) string {
+	return "cl_" + ownerCollection
+}
+
+// ======================
+// Collection Supply Caps
+// ======================
+//
+// Every mint counts against "cs_<owner>_<collection>", stored as
+// "<mintedNFTs>|<maxNFTs>|<mintedEditions>|<maxEditions>" (max 0 = unlimited).
+// Burns never give supply back: a cap of 100 means "only 100 ever".
+
+type CollectionSupply struct {
+	MintedNFTs     uint64
+	MaxNFTs        uint64
+	MintedEditions uint64
+	MaxEditions    uint64
+}
+
+// SetCollectionMaxSupply lowers the supply caps of a collection (initial caps
+// are best given to col_create).
+// Payload format: "<owner>_<collection>|<maxNFTs>|<maxEditions>"
+// - An empty field keeps the current cap.
+// - Caps can only be lowered (or set for the first time), never raised or removed,
+// and never below what has already been minted.
+// Only the collection owner may call this. A collectionUpdated event is emitted.
+//
+//go:wasmexport col_setMaxSupply
+func SetCollectionMaxSupply(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	ownerCol := parts[0]
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || *sender != collectionOwner(ownerCol) {
+		sdk.Abort("only collection owner can set supply cap")
+	}
+
+	supply := loadCollectionSupply(ownerCol)
+	supply.MaxNFTs = lowerCap(supply.MaxNFTs, parts[1], supply.MintedNFTs)
+	supply.MaxEditions = lowerCap(supply.MaxEditions, parts[2], supply.MintedEditions)
+	saveCollectionSupply(ownerCol, supply)
+
+	emitCollectionUpdated(ownerCol, false)
+	return nil
+}
+
+// parseSupplyCap reads a cap given at creation; empty = unlimited (0).
+func parseSupplyCap(s string) uint64 {
+	if s == "" {
+		return 0
+	}
+	v := mustParseUint64(s)
+	if v == 0 {
+		sdk.Abort("invalid supply cap")
+	}
+	return v
+}
+
+// lowerCap applies a requested cap (empty = keep) and aborts on raising it.
+func lowerCap(current uint64, requested string, minted uint64) uint64 {
+	if requested == "" {
+		return current
+	}
+	v := mustParseUint64(requested)
+	if v == 0 || (current > 0 && v > current) {
+		sdk.Abort("supply cap can only be lowered")
+	}
+	if v < minted {
+		sdk.Abort("supply cap below minted supply")
+	}
+	return v
+}
+
+// reserveCollectionSupply counts nfts/editions against the collection caps
+// and aborts if a cap would be exceeded.
+func reserveCollectionSupply(ownerCol string, nfts uint64, editions uint64) {
+	supply := loadCollectionSupply(ownerCol)
+	supply.MintedNFTs += nfts
+	supply.MintedEditions += editions
+	if supply.MaxNFTs > 0 && supply.MintedNFTs > supply.MaxNFTs {
+		sdk.Abort("collection max supply reached")
+	}
+	if supply.MaxEditions > 0 && supply.MintedEditions > supply.MaxEditions {
+		sdk.Abort("collection max editions reached")
+	}
+	saveCollectionSupply(ownerCol, supply)
+}
+
+func loadCollectionSupply(ownerCol string) CollectionSupply {
+	ptr := sdk.StateGetObject(colSupplyKey(ownerCol))
+	if ptr == nil || *ptr == "" {
+		return CollectionSupply{}
+	}
+	a, b, c, d := parse4(*ptr)
+	return CollectionSupply{
+		MintedNFTs:     mustParseUint64(a),
+		MaxNFTs:        mustParseUint64(b),
+		MintedEditions: mustParseUint64(c),
+		MaxEditions:    mustParseUint64(d),
+	}
+}
+
+func saveCollectionSupply(ownerCol string, s CollectionSupply) {
+	sdk.StateSetObject(colSupplyKey(ownerCol), collectionSupplyToStr(s))
+}
+
+func collectionSupplyToStr(s CollectionSupply) string {
+	b := make([]byte, 0, 48)
+	b = strconv.AppendUint(b, s.MintedNFTs, 10)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, s.MaxNFTs, 10)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, s.MintedEditions, 10)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, s.MaxEditions, 10)
+	return string(b)
+}
+
+// colSupplyKey returns "cs_<owner>_<collection>" holding the supply counters.
+func colSupplyKey(ownerCollection string) string {
+	return "cs_" + ownerCollection
+}
+
+// ==========================
+// Collection Default Royalty
+// ==========================
+
+// SetCollectionRoyalty sets or clears the default royalty of a collection.
+// Payload format: "<owner>_<collection>|<royaltyBps>|<royaltyRecipient>"
+// - royaltyBps empty or 0 clears the default (royaltyRecipient must be empty then)
+// - an empty royaltyRecipient pays the collection owner; split lists are accepted
+// The default is copied to NFTs minted afterwards without an explicit royalty;
+// royalties of NFTs already minted never change.
+// Only the collection owner may call this. A collectionUpdated event is emitted.
+//
+//go:wasmexport col_setRoyalty
+func SetCollectionRoyalty(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	ownerCol := parts[0]
+	royalty := parseRoyaltyArgs(parts[1], parts[2])
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || *sender != collectionOwner(ownerCol) {
+		sdk.Abort("only collection owner can set royalty")
+	}
+
+	if royalty == nil {
+		sdk.StateDeleteObject(colRoyaltyKey(ownerCol))
+	} else {
+		saveCollectionRoyalty(ownerCol, *royalty)
+	}
+	emitCollectionUpdated(ownerCol, false)
+	return nil
+}
+
+// ========================
+// Collection Market Policy
+// ========================
+//
+// A collection owner can narrow down which registered markets may act on
+// the NFTs minted into a collection, on top of the global market registry.
+// The policy follows those NFTs to other owners and collections:
+//
+//	allow: only the listed markets
+//	deny:  every market except the listed ones
+//
+// The policy is stored under "cmk_<owner>_<collection>" as
+// "<a|d>|<market,market,...>"; no record means every registered market.
+
+// SetCollectionMarkets sets or clears the market policy of a collection.
+// Payload format: "<owner>_<collection>|<mode>|<market,market,...>"
+// - mode is "allow", "deny" or empty (empty clears the policy; list must be empty)
+// - markets do not need to be registered yet; like add_market, an address
+// must not contain '|', '"', '\' or control characters
+// Only the collection owner may call this. A collectionUpdated event is emitted.
+//
+//go:wasmexport col_setMarkets
+func SetCollectionMarkets(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	ownerCol, mode, markets := parts[0], parts[1], parts[2]
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || *sender != collectionOwner(ownerCol) {
+		sdk.Abort("only collection owner can set markets")
+	}
+
+	var flag byte
+	switch mode {
+	case "":
+		if markets != "" {
+			sdk.Abort("mode required")
+		}
+		sdk.StateDeleteObject(colMarketsKey(ownerCol))
+		emitCollectionUpdated(ownerCol, false)
+		return nil
+	case "allow":
+		flag = 'a'
+	case "deny":
+		flag = 'd'
+	default:
+		sdk.Abort("mode must be allow or deny")
+	}
+
+	n := 0
+	start := 0
+	for i := 0; i <= len(markets); i++ {
+		if i < len(markets) && markets[i] != ',' {
+			continue
+		}
+		if i == start {
+			sdk.Abort("empty market address")
+		}
+		if hasReservedChars(markets[start:i]) {
+			sdk.Abort("invalid market address")
+		}
+		n++
+		if n > maxColMarkets {
+			sdk.Abort("too many markets")
+		}
+		start = i + 1
+	}
+
+	b := make([]byte, 0, 2+len(markets))
+	b = append(b, flag, '|')
+	b = append(b, markets...)
+	sdk.StateSetObject(colMarketsKey(ownerCol), string(b))
+	emitCollectionUpdated(ownerCol, false)
+	return nil
+}
+
+// collectionAllowsMarket applies the collection market policy to market.
+func collectionAllowsMarket(ownerCol string, market string) bool {
+	ptr := sdk.StateGetObject(colMarketsKey(ownerCol))
+	if ptr == nil || *ptr == "" {
+		return true
+	}
+	p := *ptr
+	listed := containsInList(p[2:], ',', market)
+	if p[0] == 'a' {
+		return listed
+	}
+	return !listed
+}
+
+// colMarketsKey returns "cmk_<owner>_<collection>" holding the market policy.
+func colMarketsKey(ownerCollection string) string {
+	return "cmk_" + ownerCollection
+}
+
+// ===================
+// Collection Minters
+// ===================
+//
+// Only the collection owner and minters granted by the owner may mint into a
+// collection. Minters are stored as a '|'-joined list under "cm_<owner>_<collection>".
+
+// AddCollectionMinter grants an address the right to mint into a collection.
+// Payload format: "<owner>_<collection>|<minterAddress>"
+// Only the collection owner may call this. A minter event is emitted.
+//
+//go:wasmexport col_addMinter
+func AddCollectionMinter(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	ownerCol, minter := parseMinterPayload(payload)
+	if !isValidAccount(minter) {
+		sdk.Abort("invalid minter address")
+	}
+
+	key := colMintersKey(ownerCol)
+	existing := sdk.StateGetObject(key)
+	if existing != nil && *existing != "" {
+		if containsInCSV(*existing, minter) {
+			sdk.Abort("minter already set")
+		}
+		if countCSV(*existing) >= maxMinters {
+			sdk.Abort("too many minters")
+		}
+		sdk.StateSetObject(key, *existing+"|"+minter)
+	} else {
+		sdk.StateSetObject(key, minter)
+	}
+
+	emitMinter(ownerCol, minter, true)
+	return nil
+}
+
+// RemoveCollectionMinter revokes the minting right of an address.
+// Payload format: "<owner>_<collection>|<minterAddress>"
+// Only the collection owner may call this. A minter event is emitted.
+//
+//go:wasmexport col_removeMinter
+func RemoveCollectionMinter(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	ownerCol, minter := parseMinterPayload(payload)
+
+	key := colMintersKey(ownerCol)
+	existing := sdk.StateGetObject(key)
+	if existing == nil || *existing == "" {
+		sdk.Abort("minter not found")
+	}
+	newCSV, found := removeFromCSV(*existing, minter)
+	if !found {
+		sdk.Abort("minter not found")
+	}
+	if newCSV == "" {
+		sdk.StateDeleteObject(key)
+	} else {
+		sdk.StateSetObject(key, newCSV)
+	}
+
+	emitMinter(ownerCol, minter, false)
+	return nil
+}
+
+// parseMinterPayload splits "<owner>_<collection>|<minter>" and makes sure the
+// collection exists and msg.sender owns it.
+func parseMinterPayload(payload *string) (string, string) {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	ownerCol, minter := split2Str(*payload)
+	if minter == "" {
+		sdk.Abort("minter address required")
+	}
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	owner := collectionOwner(ownerCol)
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || *sender != owner {
+		sdk.Abort("only collection owner can manage minters")
+	}
+	if minter == owner {
+		sdk.Abort("owner is always a minter")
+	}
+	return ownerCol, minter
+}
+
 // =============================
 // Internal Collection Functions
 // =============================
@@ -74,6 +642,21 @@ func loadCollection(ownerCollection string) *string {
 	return ptr
 }
 
+// canMintInto returns true if minter is the owner of ownerCollection
+// or one of its granted minters.
+func canMintInto(ownerCollection string, minter string) bool {
+	if minter == collectionOwner(ownerCollection) {
+		return true
+	}
+	ptr := sdk.StateGetObject(colMintersKey(ownerCollection))
+	return ptr != nil && containsInCSV(*ptr, minter)
+}
+
+// colMintersKey returns "cm_<owner>_<collection>" holding the '|'-joined minter list.
+func colMintersKey(ownerCollection string) string {
+	return "cm_" + ownerCollection
+}
+
 // colIndexKey returns the index "c_<owner>_<collection>" string for state lookups.
 func colIndexKey(owner, col string) string {
 	b := make([]byte, 0, 2+len(owner)+1+len(col))
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// ===================
+// DUTCH-AUCTION DROPS
+// ===================
+//
+// A collection owner can open a drop on a collection: buyers mint a new NFT
+// from a fixed template at a price that falls linearly from the start price
+// at the start block to the end price (the floor) at the end block. After the
+// end block the drop keeps selling at the floor until the collection supply
+// cap is reached or the owner closes it.
+//
+// Drop mints go through the regular mint code path into the drop collection
+// (supply caps, default royalties and mint events apply), then the NFT moves
+// to the buyer's collection. The full price is paid to the collection owner.
+//
+// The drop is stored under "cd_<owner>_<collection>" as
+// "<startPrice>|<endPrice>|<startBlock>|<endBlock>|<asset>|<walletLimit>|<template>"
+// where template is "<name>|<desc>|<singleTransfer>|<royaltyBps>|<royaltyRecipient>|<metadata>".
+// Mints per wallet are counted under "cdw_<owner>_<collection>|<wallet>" and
+// survive reconfiguration of the drop.
+
+type Drop struct {
+	StartPrice  uint64
+	EndPrice    uint64
+	StartBlock  uint64
+	EndBlock    uint64
+	Asset       sdk.Asset
+	WalletLimit uint64 // 0 = unlimited
+	Template    mintArgs
+}
+
+// SetDrop opens, replaces or closes the drop of a collection.
+// Payload formats:
+//
+//	"<owner>_<collection>"  → close the drop
+//	"<owner>_<collection>|<startPrice>|<endPrice>|<startBlock>|<endBlock>|<asset>|<walletLimit>|<name>|<desc>|<singleTransfer>|<royaltyBps>|<royaltyRecipient>|<metadata>"
+//
+// - endPrice must not exceed startPrice; endBlock must be after startBlock
+// and at most maxDropBlocks later
+// - asset is "hive" or "hbd"; walletLimit empty or 0 means unlimited
+// - the template fields are the same as for nft_mintRoyalty (editions are always 1);
+// leave royaltyBps and royaltyRecipient empty for the collection default
+// Only the collection owner may call this. A drop or dropCancel event is emitted.
+//
+//go:wasmexport col_setDrop
+func SetDrop(payload *string) *string {
+	requireNotPaused(pauseCollection)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	p := *payload
+	ownerCol := p
+	cfg := ""
+	if i := indexByte(p, '|'); i >= 0 {
+		ownerCol, cfg = p[:i], p[i+1:]
+	}
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || *sender != collectionOwner(ownerCol) {
+		sdk.Abort("only collection owner can set drop")
+	}
+
+	if cfg == "" {
+		if loadDrop(ownerCol) == nil {
+			sdk.Abort("drop not found")
+		}
+		sdk.StateDeleteObject(colDropKey(ownerCol))
+		emitDrop("dropCancel", ownerCol, nil)
+		return nil
+	}
+
+	d := parseDrop(cfg)
+	if d.EndPrice > d.StartPrice {
+		sdk.Abort("end price above start price")
+	}
+	if d.EndBlock <= d.StartBlock {
+		sdk.Abort("end block must be after start block")
+	}
+	if d.EndBlock-d.StartBlock > maxDropBlocks {
+		sdk.Abort("drop too long")
+	}
+	validateMintArgs(d.Template.name, d.Template.desc)
+
+	sdk.StateSetObject(colDropKey(ownerCol), cfg)
+	emitDrop("drop", ownerCol, &d)
+	return nil
+}
+
+// DropMint mints one NFT from the drop of a collection at the current price.
+// Payload format: "<owner>_<collection>|<buyerOwner>_<buyerCollection>"
+// - the caller must provide a HIVE/HBD intent covering the current price
+// - the buyer collection receives the NFT and must belong to the caller
+// The price is drawn and paid to the collection owner; mint, transfer and
+// sale events are emitted.
+//
+//go:wasmexport drop_mint
+func DropMint(payload *string) *string {
+	requireNotPaused(pauseMint | pauseTrade)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 2)
+	ownerCol, target := parts[0], parts[1]
+	d := loadDrop(ownerCol)
+	if d == nil {
+		sdk.Abort("drop not found")
+	}
+	height := currentBlockHeight()
+	if height < d.StartBlock {
+		sdk.Abort("drop not started")
+	}
+	buyer := *sdk.GetEnvKey("msg.caller")
+	loadCollection(target) // make sure the collection exists
+	if collectionOwner(target) != buyer {
+		sdk.Abort("target collection not owned by buyer")
+	}
+	walletKey := dropWalletKey(ownerCol, buyer)
+	minted := uint64(0)
+	if ptr := sdk.StateGetObject(walletKey); ptr != nil && *ptr != "" {
+		minted = mustParseUint64(*ptr)
+	}
+	if d.WalletLimit > 0 && minted >= d.WalletLimit {
+		sdk.Abort("wallet limit reached")
+	}
+
+	price := dropPrice(*d, height)
+	creator := collectionOwner(ownerCol)
+	sdk.HiveDraw(int64(price), d.Asset)
+	sdk.HiveTransfer(sdk.Address(creator), int64(price), d.Asset)
+
+	reserveCollectionSupply(ownerCol, 1, 1)
+	nftID := getNFTCount()
+	mintNFT(nftID, ownerCol, creator, d.Template, loadCollectionRoyalty(ownerCol))
+	setNFTCount(nftID + 1)
+	if target != ownerCol {
+		moveToken(nftID, 0, 1, ownerCol, target)
+	}
+	sdk.StateSetObject(walletKey, strconv.FormatUint(minted+1, 10))
+	emitSale(nftID, nil, creator, buyer, price, d.Asset, 0)
+	return nil
+}
+
+// GetDrop returns the drop of a collection with its price at the current block.
+//
+// Payload: "<owner>_<collection>"
+//
+// Returns: "<startPrice>|<endPrice>|<startBlock>|<endBlock>|<asset>|<walletLimit>|<currentPrice>"
+// or an empty string if the collection has no drop.
+//
+//go:wasmexport drop_get
+func GetDrop(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	out := ""
+	if d := loadDrop(*payload); d != nil {
+		b := make([]byte, 0, 112)
+		b = strconv.AppendUint(b, d.StartPrice, 10)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, d.EndPrice, 10)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, d.StartBlock, 10)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, d.EndBlock, 10)
+		b = append(b, '|')
+		b = append(b, d.Asset...)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, d.WalletLimit, 10)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, dropPrice(*d, currentBlockHeight()), 10)
+		out = string(b)
+	}
+	return &out
+}
+
+// ================
+// Internal Helpers
+// ================
+
+// dropPrice interpolates the price linearly between start and end block.
+// The split division avoids overflowing (start-end)*elapsed.
+func dropPrice(d Drop, height uint64) uint64 {
+	if height <= d.StartBlock {
+		return d.StartPrice
+	}
+	if height >= d.EndBlock {
+		return d.EndPrice
+	}
+	span := d.StartPrice - d.EndPrice
+	duration := d.EndBlock - d.StartBlock
+	elapsed := height - d.StartBlock
+	return d.StartPrice - (span/duration*elapsed + span%duration*elapsed/duration)
+}
+
+// parseDrop parses a stored or submitted drop configuration (without the collection).
+func parseDrop(cfg string) Drop {
+	parts := splitFixedPipe(cfg, 12)
+	d := Drop{
+		StartPrice:  parsePrice(parts[0]),
+		EndPrice:    parsePrice(parts[1]),
+		StartBlock:  mustParseUint64(parts[2]),
+		EndBlock:    mustParseUint64(parts[3]),
+		Asset:       parseSaleAsset(parts[4]),
+		WalletLimit: parseOptionalUint64(parts[5]),
+		Template: mintArgs{
+			name:     parts[6],
+			desc:     parts[7],
+			single:   parts[8] == "true",
+			editions: 1,
+			royalty:  parseRoyaltyArgs(parts[9], parts[10]),
+			meta:     parts[11],
+		},
+	}
+	return d
+}
+
+func loadDrop(ownerCol string) *Drop {
+	ptr := sdk.StateGetObject(colDropKey(ownerCol))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	d := parseDrop(*ptr)
+	return &d
+}
+
+// colDropKey returns "cd_<owner>_<collection>" holding the drop configuration.
+func colDropKey(ownerCollection string) string {
+	return "cd_" + ownerCollection
+}
+
+// dropWalletKey returns "cdw_<owner>_<collection>|<wallet>" holding the mints of a wallet.
+func dropWalletKey(ownerCollection string, wallet string) string {
+	return "cdw_" + ownerCollection + "|" + wallet
+}
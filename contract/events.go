@@ -50,9 +50,11 @@ func emitEventJSON(eventType string, attributesJSON string) {
 //
 // EmitMintEvent formats and emits:
 //
-//	{"type":"mint","attributes":{"id":123,"cr":"addr","oc":"owner_col","ed":100},"tx":"<tx>"}
-func EmitMintEvent(id uint64, creator string, ownerCol string, editions uint32) {
-	attrs := make([]byte, 0, len(creator)+len(ownerCol)+32)
+//	{"type":"mint","attributes":{"id":123,"cr":"addr","oc":"owner_col","ed":100,"ro":500,"rr":"recipient"},"tx":"<tx>"}
+//
+// Royalty attributes ("ro" in basis points, "rr" recipient) are only emitted if royaltyBps > 0.
+func EmitMintEvent(id uint64, creator string, ownerCol string, editions uint32, royaltyBps uint32, royaltyRecipient string) {
+	attrs := make([]byte, 0, len(creator)+len(ownerCol)+len(royaltyRecipient)+56)
 	attrs = append(attrs, '{')
 
 	// "id":123
@@ -73,6 +75,15 @@ func EmitMintEvent(id uint64, creator string, ownerCol string, editions uint32)
 	attrs = append(attrs, ',', '"', 'e', 'd', '"', ':')
 	attrs = strconv.AppendUint(attrs, uint64(editions), 10)
 
+	// Optional royalty
+	if royaltyBps > 0 {
+		attrs = append(attrs, ',', '"', 'r', 'o', '"', ':')
+		attrs = strconv.AppendUint(attrs, uint64(royaltyBps), 10)
+		attrs = append(attrs, ',', '"', 'r', 'r', '"', ':', '"')
+		attrs = append(attrs, royaltyRecipient...)
+		attrs = append(attrs, '"')
+	}
+
 	attrs = append(attrs, '}')
 	emitEventJSON("mint", string(attrs))
 }
@@ -111,6 +122,38 @@ func emitTransfer(id uint64, ed *uint32, from string, to string) {
 	emitEventJSON("transfer", string(attrs))
 }
 
+// emitTransferRange logs one aggregated transfer for an inclusive edition
+// range. It has its own type so indexers that only know per-token "transfer"
+// events (with "ed") do not misread it as a move of the base NFT. Example:
+//
+//	{"type":"transferRange","attributes":{"id":123,"ef":0,"et":99,"fr":"alice_0","to":"bob_1"},"tx":"<tx>"}
+func emitTransferRange(id uint64, fromEd, toEd uint32, from string, to string) {
+	attrs := make([]byte, 0, len(from)+len(to)+64)
+	attrs = append(attrs, '{')
+
+	// "id":123
+	attrs = append(attrs, '"', 'i', 'd', '"', ':')
+	attrs = strconv.AppendUint(attrs, id, 10)
+
+	// "ef":<first edition>,"et":<last edition>
+	attrs = append(attrs, ',', '"', 'e', 'f', '"', ':')
+	attrs = strconv.AppendUint(attrs, uint64(fromEd), 10)
+	attrs = append(attrs, ',', '"', 'e', 't', '"', ':')
+	attrs = strconv.AppendUint(attrs, uint64(toEd), 10)
+
+	// "fr":"fromAddr"
+	attrs = append(attrs, ',', '"', 'f', 'r', '"', ':', '"')
+	attrs = append(attrs, from...)
+	attrs = append(attrs, '"')
+
+	// "to":"toAddr"
+	attrs = append(attrs, ',', '"', 't', 'o', '"', ':', '"')
+	attrs = append(attrs, to...)
+	attrs = append(attrs, '"', '}')
+
+	emitEventJSON("transferRange", string(attrs))
+}
+
 // ==============
 // NFT Burn Event
 // ==============
@@ -142,6 +185,81 @@ func emitBurn(id uint64, ed *uint32, owner string, _ string) {
 	emitEventJSON("burn", string(attrs))
 }
 
+// ==================
+// NFT Approval Event
+// ==================
+//
+// emitApproval logs a granted or revoked per-edition approval. Example:
+//
+//	{"type":"approval","attributes":{"id":123,"ed":1,"ow":"ownerAddr","ap":"approvedAddr"},"tx":"<tx>"}
+//
+// An empty "ap" means the approval was revoked. Approvals cleared implicitly
+// by a transfer or burn do not emit an extra event.
+func emitApproval(id uint64, ed *uint32, owner string, approved string) {
+	attrs := make([]byte, 0, len(owner)+len(approved)+48)
+	attrs = append(attrs, '{')
+
+	// "id":123
+	attrs = append(attrs, '"', 'i', 'd', '"', ':')
+	attrs = strconv.AppendUint(attrs, id, 10)
+
+	// Optional edition
+	if ed != nil {
+		attrs = append(attrs, ',', '"', 'e', 'd', '"', ':')
+		attrs = strconv.AppendUint(attrs, uint64(*ed), 10)
+	}
+
+	// "ow":"owner"
+	attrs = append(attrs, ',', '"', 'o', 'w', '"', ':', '"')
+	attrs = append(attrs, owner...)
+	attrs = append(attrs, '"')
+
+	// "ap":"approved"
+	attrs = append(attrs, ',', '"', 'a', 'p', '"', ':', '"')
+	attrs = append(attrs, approved...)
+	attrs = append(attrs, '"', '}')
+
+	emitEventJSON("approval", string(attrs))
+}
+
+// ==============
+// Operator Event
+// ==============
+//
+// emitOperator logs a granted or revoked operator approval. Example:
+//
+//	{"type":"operator","attributes":{"ow":"ownerAddr","op":"operatorAddr","oc":"owner_col","ap":true},"tx":"<tx>"}
+//
+// "oc" is only emitted if the approval is limited to a single collection.
+func emitOperator(owner string, operator string, scope string, approved bool) {
+	attrs := make([]byte, 0, len(owner)+len(operator)+len(scope)+48)
+	attrs = append(attrs, '{')
+
+	// "ow":"owner"
+	attrs = append(attrs, '"', 'o', 'w', '"', ':', '"')
+	attrs = append(attrs, owner...)
+	attrs = append(attrs, '"')
+
+	// "op":"operator"
+	attrs = append(attrs, ',', '"', 'o', 'p', '"', ':', '"')
+	attrs = append(attrs, operator...)
+	attrs = append(attrs, '"')
+
+	// Optional collection scope
+	if scope != "" {
+		attrs = append(attrs, ',', '"', 'o', 'c', '"', ':', '"')
+		attrs = append(attrs, scope...)
+		attrs = append(attrs, '"')
+	}
+
+	// "ap":true|false
+	attrs = append(attrs, ',', '"', 'a', 'p', '"', ':')
+	attrs = strconv.AppendBool(attrs, approved)
+	attrs = append(attrs, '}')
+
+	emitEventJSON("operator", string(attrs))
+}
+
 // ========================
 // Collection Created Event
 // ========================
@@ -166,3 +284,468 @@ func EmitCollectionCreatedEvent(id uint64, creator string) {
 
 	emitEventJSON("collection", string(attrs))
 }
+
+// =========================
+// Collection Transfer Event
+// =========================
+//
+// emitCollectionTransfer logs a collection ownership change. Example:
+//
+//	{"type":"collectionTransfer","attributes":{"oc":"owner_col","fr":"oldOwner","to":"newOwner"},"tx":"<tx>"}
+func emitCollectionTransfer(ownerCol string, from string, to string) {
+	attrs := make([]byte, 0, len(ownerCol)+len(from)+len(to)+32)
+	attrs = append(attrs, '{')
+
+	// "oc":"owner_collection"
+	attrs = append(attrs, '"', 'o', 'c', '"', ':', '"')
+	attrs = append(attrs, ownerCol...)
+	attrs = append(attrs, '"')
+
+	// "fr":"oldOwner"
+	attrs = append(attrs, ',', '"', 'f', 'r', '"', ':', '"')
+	attrs = append(attrs, from...)
+	attrs = append(attrs, '"')
+
+	// "to":"newOwner"
+	attrs = append(attrs, ',', '"', 't', 'o', '"', ':', '"')
+	attrs = append(attrs, to...)
+	attrs = append(attrs, '"', '}')
+
+	emitEventJSON("collectionTransfer", string(attrs))
+}
+
+// ========================
+// Collection Updated Event
+// ========================
+//
+// emitCollectionUpdated logs a collection update or lock. Example:
+//
+//	{"type":"collectionUpdated","attributes":{"oc":"owner_col","lk":true},"tx":"<tx>"}
+//
+// "lk" is only emitted by col_lock, which permanently locks the collection.
+func emitCollectionUpdated(ownerCol string, locked bool) {
+	attrs := make([]byte, 0, len(ownerCol)+24)
+	attrs = append(attrs, '{')
+
+	// "oc":"owner_collection"
+	attrs = append(attrs, '"', 'o', 'c', '"', ':', '"')
+	attrs = append(attrs, ownerCol...)
+	attrs = append(attrs, '"')
+
+	// Optional lock flag
+	if locked {
+		attrs = append(attrs, ',', '"', 'l', 'k', '"', ':', 't', 'r', 'u', 'e')
+	}
+	attrs = append(attrs, '}')
+
+	emitEventJSON("collectionUpdated", string(attrs))
+}
+
+// ============
+// Minter Event
+// ============
+//
+// emitMinter logs a granted or revoked collection minter. Example:
+//
+//	{"type":"minter","attributes":{"oc":"owner_col","mi":"minterAddr","ap":true},"tx":"<tx>"}
+func emitMinter(ownerCol string, minter string, approved bool) {
+	attrs := make([]byte, 0, len(ownerCol)+len(minter)+40)
+	attrs = append(attrs, '{')
+
+	// "oc":"owner_collection"
+	attrs = append(attrs, '"', 'o', 'c', '"', ':', '"')
+	attrs = append(attrs, ownerCol...)
+	attrs = append(attrs, '"')
+
+	// "mi":"minter"
+	attrs = append(attrs, ',', '"', 'm', 'i', '"', ':', '"')
+	attrs = append(attrs, minter...)
+	attrs = append(attrs, '"')
+
+	// "ap":true|false
+	attrs = append(attrs, ',', '"', 'a', 'p', '"', ':')
+	attrs = strconv.AppendBool(attrs, approved)
+	attrs = append(attrs, '}')
+
+	emitEventJSON("minter", string(attrs))
+}
+
+// ==============
+// Metadata Event
+// ==============
+//
+// emitMetadata logs a metadata change of an NFT. Examples:
+//
+//	{"type":"metadata","attributes":{"id":7,"by":"hive:creator"},"tx":"<tx>"}
+//	{"type":"metadata","attributes":{"id":7,"by":"hive:creator","up":"hive:game"},"tx":"<tx>"}
+//	{"type":"metadata","attributes":{"id":7,"by":"hive:creator","fz":true},"tx":"<tx>"}
+//
+// "up" is only emitted when the metadata updater changes (empty = removed),
+// "fz" only when the metadata gets frozen.
+func emitMetadata(id uint64, by string, frozen bool, updater *string) {
+	upLen := 0
+	if updater != nil {
+		upLen = len(*updater)
+	}
+	attrs := make([]byte, 0, len(by)+upLen+48)
+	attrs = append(attrs, '{')
+
+	// "id":123
+	attrs = append(attrs, '"', 'i', 'd', '"', ':')
+	attrs = strconv.AppendUint(attrs, id, 10)
+
+	// "by":"caller"
+	attrs = append(attrs, ',', '"', 'b', 'y', '"', ':', '"')
+	attrs = append(attrs, by...)
+	attrs = append(attrs, '"')
+
+	// Optional updater
+	if updater != nil {
+		attrs = append(attrs, ',', '"', 'u', 'p', '"', ':', '"')
+		attrs = append(attrs, (*updater)...)
+		attrs = append(attrs, '"')
+	}
+
+	// Optional freeze flag
+	if frozen {
+		attrs = append(attrs, ',', '"', 'f', 'z', '"', ':', 't', 'r', 'u', 'e')
+	}
+	attrs = append(attrs, '}')
+
+	emitEventJSON("metadata", string(attrs))
+}
+
+// ============
+// Admin Events
+// ============
+//
+// emitAdmin logs an admin handover step. Examples:
+//
+//	{"type":"adminProposed","attributes":{"fr":"currentAdmin","to":"proposedAdmin"},"tx":"<tx>"}
+//	{"type":"adminChanged","attributes":{"fr":"previousAdmin","to":"newAdmin"},"tx":"<tx>"}
+func emitAdmin(eventType string, from string, to string) {
+	attrs := make([]byte, 0, len(from)+len(to)+24)
+	attrs = append(attrs, '{')
+
+	// "fr":"fromAddr"
+	attrs = append(attrs, '"', 'f', 'r', '"', ':', '"')
+	attrs = append(attrs, from...)
+	attrs = append(attrs, '"')
+
+	// "to":"toAddr"
+	attrs = append(attrs, ',', '"', 't', 'o', '"', ':', '"')
+	attrs = append(attrs, to...)
+	attrs = append(attrs, '"', '}')
+
+	emitEventJSON(eventType, string(attrs))
+}
+
+// ==========
+// Role Event
+// ==========
+//
+// emitRole logs a role grant or revocation. Example:
+//
+//	{"type":"role","attributes":{"rl":"PAUSER","ac":"hive:ops","ap":true},"tx":"<tx>"}
+func emitRole(role string, account string, granted bool) {
+	attrs := make([]byte, 0, len(role)+len(account)+32)
+	attrs = append(attrs, '{')
+
+	// "rl":"role"
+	attrs = append(attrs, '"', 'r', 'l', '"', ':', '"')
+	attrs = append(attrs, role...)
+	attrs = append(attrs, '"')
+
+	// "ac":"account"
+	attrs = append(attrs, ',', '"', 'a', 'c', '"', ':', '"')
+	attrs = append(attrs, account...)
+	attrs = append(attrs, '"')
+
+	// "ap":true|false
+	attrs = append(attrs, ',', '"', 'a', 'p', '"', ':')
+	attrs = strconv.AppendBool(attrs, granted)
+	attrs = append(attrs, '}')
+
+	emitEventJSON("role", string(attrs))
+}
+
+// ===========
+// Pause Event
+// ===========
+//
+// emitPause logs the paused classes bitmask after a pause/unpause. Example:
+//
+//	{"type":"pause","attributes":{"pc":3,"by":"hive:security"},"tx":"<tx>"}
+//
+// "pc" is 0 once everything is resumed.
+func emitPause(mask uint64, by string) {
+	attrs := make([]byte, 0, len(by)+40)
+	attrs = append(attrs, '{')
+
+	// "pc":mask
+	attrs = append(attrs, '"', 'p', 'c', '"', ':')
+	attrs = strconv.AppendUint(attrs, mask, 10)
+
+	// "by":"caller"
+	attrs = append(attrs, ',', '"', 'b', 'y', '"', ':', '"')
+	attrs = append(attrs, by...)
+	attrs = append(attrs, '"', '}')
+
+	emitEventJSON("pause", string(attrs))
+}
+
+// ============
+// Market Event
+// ============
+//
+// emitMarket logs a market registry change. Example:
+//
+//	{"type":"market","attributes":{"mk":"contract:market","ap":true},"tx":"<tx>"}
+//
+// "ap" is true when the market got registered or updated (re-read it via
+// market_get) and false when it got removed.
+func emitMarket(market string, registered bool) {
+	attrs := make([]byte, 0, len(market)+24)
+	attrs = append(attrs, '{')
+
+	// "mk":"market"
+	attrs = append(attrs, '"', 'm', 'k', '"', ':', '"')
+	attrs = append(attrs, market...)
+	attrs = append(attrs, '"')
+
+	// "ap":true|false
+	attrs = append(attrs, ',', '"', 'a', 'p', '"', ':')
+	attrs = strconv.AppendBool(attrs, registered)
+	attrs = append(attrs, '}')
+
+	emitEventJSON("market", string(attrs))
+}
+
+// ==============
+// Listing Events
+// ==============
+//
+// emitListing logs a listing being created/replaced ("listing") or cancelled
+// ("listingCancel"). Example:
+//
+//	{"type":"listing","attributes":{"id":7,"ed":2,"sl":"hive:seller","pr":10000,"as":"hive"},"tx":"<tx>"}
+func emitListing(eventType string, id uint64, ed *uint32, l Listing) {
+	attrs := make([]byte, 0, len(l.Seller)+80)
+	attrs = append(attrs, '{')
+	attrs = appendTokenAttrs(attrs, id, ed)
+	attrs = appendPriceAttrs(attrs, l.Seller, l.Price, l.Asset)
+	attrs = append(attrs, '}')
+	emitEventJSON(eventType, string(attrs))
+}
+
+// emitSale logs a completed sale; "ro" is the royalty paid out of "pr". Example:
+//
+//	{"type":"sale","attributes":{"id":7,"sl":"hive:seller","pr":10000,"as":"hive","bu":"hive:buyer","ro":500},"tx":"<tx>"}
+func emitSale(id uint64, ed *uint32, seller string, buyer string, price uint64, asset sdk.Asset, royalty uint64) {
+	attrs := make([]byte, 0, len(seller)+len(buyer)+96)
+	attrs = append(attrs, '{')
+	attrs = appendTokenAttrs(attrs, id, ed)
+	attrs = appendPriceAttrs(attrs, seller, price, asset)
+
+	// "bu":"buyer"
+	attrs = append(attrs, ',', '"', 'b', 'u', '"', ':', '"')
+	attrs = append(attrs, buyer...)
+	attrs = append(attrs, '"')
+
+	// "ro":royalty
+	attrs = append(attrs, ',', '"', 'r', 'o', '"', ':')
+	attrs = strconv.AppendUint(attrs, royalty, 10)
+	attrs = append(attrs, '}')
+
+	emitEventJSON("sale", string(attrs))
+}
+
+// appendTokenAttrs appends "id":<id> and the optional "ed":<edition>.
+func appendTokenAttrs(attrs []byte, id uint64, ed *uint32) []byte {
+	attrs = append(attrs, '"', 'i', 'd', '"', ':')
+	attrs = strconv.AppendUint(attrs, id, 10)
+	if ed != nil {
+		attrs = append(attrs, ',', '"', 'e', 'd', '"', ':')
+		attrs = strconv.AppendUint(attrs, uint64(*ed), 10)
+	}
+	return attrs
+}
+
+// appendPriceAttrs appends ,"sl":"<seller>","pr":<price>,"as":"<asset>".
+func appendPriceAttrs(attrs []byte, seller string, price uint64, asset sdk.Asset) []byte {
+	attrs = append(attrs, ',', '"', 's', 'l', '"', ':', '"')
+	attrs = append(attrs, seller...)
+	attrs = append(attrs, '"')
+
+	attrs = append(attrs, ',', '"', 'p', 'r', '"', ':')
+	attrs = strconv.AppendUint(attrs, price, 10)
+
+	attrs = append(attrs, ',', '"', 'a', 's', '"', ':', '"')
+	attrs = append(attrs, asset...)
+	attrs = append(attrs, '"')
+	return attrs
+}
+
+// ==============
+// Auction Events
+// ==============
+//
+// emitAuction logs an auction being opened ("auction") or closed
+// ("auctionEnd"). "pr" is the reserve price, "eh" the end block height;
+// "auctionEnd" additionally carries the winner ("bu") and winning bid ("bi")
+// if there was one. Example:
+//
+//	{"type":"auction","attributes":{"id":7,"sl":"hive:seller","pr":5000,"as":"hive","eh":123456},"tx":"<tx>"}
+func emitAuction(eventType string, id uint64, ed *uint32, a Auction) {
+	attrs := make([]byte, 0, len(a.Seller)+len(a.Bidder)+112)
+	attrs = append(attrs, '{')
+	attrs = appendTokenAttrs(attrs, id, ed)
+	attrs = appendPriceAttrs(attrs, a.Seller, a.Reserve, a.Asset)
+
+	// "eh":endHeight
+	attrs = append(attrs, ',', '"', 'e', 'h', '"', ':')
+	attrs = strconv.AppendUint(attrs, a.EndHeight, 10)
+
+	if eventType == "auctionEnd" && a.Bidder != "" {
+		attrs = append(attrs, ',', '"', 'b', 'u', '"', ':', '"')
+		attrs = append(attrs, a.Bidder...)
+		attrs = append(attrs, '"', ',', '"', 'b', 'i', '"', ':')
+		attrs = strconv.AppendUint(attrs, a.Bid, 10)
+	}
+	attrs = append(attrs, '}')
+	emitEventJSON(eventType, string(attrs))
+}
+
+// emitBid logs a new highest bid. Example:
+//
+//	{"type":"bid","attributes":{"id":7,"bu":"hive:bidder","bi":6000,"as":"hive"},"tx":"<tx>"}
+func emitBid(id uint64, ed *uint32, bidder string, amount uint64, asset sdk.Asset) {
+	attrs := make([]byte, 0, len(bidder)+72)
+	attrs = append(attrs, '{')
+	attrs = appendTokenAttrs(attrs, id, ed)
+
+	// "bu":"bidder"
+	attrs = append(attrs, ',', '"', 'b', 'u', '"', ':', '"')
+	attrs = append(attrs, bidder...)
+	attrs = append(attrs, '"')
+
+	// "bi":amount
+	attrs = append(attrs, ',', '"', 'b', 'i', '"', ':')
+	attrs = strconv.AppendUint(attrs, amount, 10)
+
+	// "as":"asset"
+	attrs = append(attrs, ',', '"', 'a', 's', '"', ':', '"')
+	attrs = append(attrs, asset...)
+	attrs = append(attrs, '"', '}')
+
+	emitEventJSON("bid", string(attrs))
+}
+
+// ============
+// Offer Events
+// ============
+//
+// emitOffer logs an offer being made ("offer"), cancelled or refunded
+// ("offerCancel") or accepted ("offerAccept", followed by transfer and sale
+// events). "of" is the offer ID, "pr" the (per-item) amount and "eh" the
+// expiry block height. Collection offers carry "oc" and the open item count
+// "qt" instead of the token. Example:
+//
+//	{"type":"offer","attributes":{"of":3,"id":7,"ed":2,"bu":"hive:bidder","pr":8000,"as":"hbd","eh":123456},"tx":"<tx>"}
+func emitOffer(eventType string, offerID uint64, ed *uint32, o Offer) {
+	attrs := make([]byte, 0, len(o.Bidder)+len(o.Collection)+128)
+	attrs = append(attrs, '{')
+
+	// "of":offerID
+	attrs = append(attrs, '"', 'o', 'f', '"', ':')
+	attrs = strconv.AppendUint(attrs, offerID, 10)
+	attrs = append(attrs, ',')
+	if o.Collection != "" {
+		attrs = appendCollectionOfferAttrs(attrs, o)
+	} else {
+		attrs = appendTokenAttrs(attrs, o.NftID, ed)
+	}
+
+	// "bu":"bidder"
+	attrs = append(attrs, ',', '"', 'b', 'u', '"', ':', '"')
+	attrs = append(attrs, o.Bidder...)
+	attrs = append(attrs, '"')
+
+	// "pr":amount,"as":"asset","eh":expiry
+	attrs = append(attrs, ',', '"', 'p', 'r', '"', ':')
+	attrs = strconv.AppendUint(attrs, o.Amount, 10)
+	attrs = append(attrs, ',', '"', 'a', 's', '"', ':', '"')
+	attrs = append(attrs, o.Asset...)
+	attrs = append(attrs, '"', ',', '"', 'e', 'h', '"', ':')
+	attrs = strconv.AppendUint(attrs, o.Expiry, 10)
+	attrs = append(attrs, '}')
+
+	emitEventJSON(eventType, string(attrs))
+}
+
+// emitOfferFill logs one item of a collection offer being filled by a token,
+// followed by transfer and sale events. "qt" is the number of items still
+// open (0 = offer closed). Example:
+//
+//	{"type":"offerFill","attributes":{"of":4,"id":9,"oc":"owner_col","qt":2},"tx":"<tx>"}
+func emitOfferFill(offerID uint64, id uint64, ed *uint32, o Offer) {
+	attrs := make([]byte, 0, len(o.Collection)+80)
+	attrs = append(attrs, '{')
+
+	// "of":offerID
+	attrs = append(attrs, '"', 'o', 'f', '"', ':')
+	attrs = strconv.AppendUint(attrs, offerID, 10)
+	attrs = append(attrs, ',')
+	attrs = appendTokenAttrs(attrs, id, ed)
+	attrs = append(attrs, ',')
+	attrs = appendCollectionOfferAttrs(attrs, o)
+	attrs = append(attrs, '}')
+
+	emitEventJSON("offerFill", string(attrs))
+}
+
+// appendCollectionOfferAttrs appends "oc":"<collection>","qt":<remaining>.
+func appendCollectionOfferAttrs(attrs []byte, o Offer) []byte {
+	attrs = append(attrs, '"', 'o', 'c', '"', ':', '"')
+	attrs = append(attrs, o.Collection...)
+	attrs = append(attrs, '"', ',', '"', 'q', 't', '"', ':')
+	attrs = strconv.AppendUint(attrs, o.Remaining, 10)
+	return attrs
+}
+
+// ===========
+// Drop Events
+// ===========
+//
+// emitDrop logs a drop being opened/replaced ("drop") or closed ("dropCancel",
+// collection only). "sp"/"ep" are the start/end price, "sb"/"eb" the start/end
+// block and "wl" the per-wallet limit (0 = unlimited). Example:
+//
+//	{"type":"drop","attributes":{"oc":"owner_col","sp":10000,"ep":1000,"sb":100,"eb":1100,"as":"hive","wl":2},"tx":"<tx>"}
+func emitDrop(eventType string, ownerCol string, d *Drop) {
+	attrs := make([]byte, 0, len(ownerCol)+128)
+	attrs = append(attrs, '{')
+
+	// "oc":"owner_collection"
+	attrs = append(attrs, '"', 'o', 'c', '"', ':', '"')
+	attrs = append(attrs, ownerCol...)
+	attrs = append(attrs, '"')
+
+	if d != nil {
+		attrs = append(attrs, ',', '"', 's', 'p', '"', ':')
+		attrs = strconv.AppendUint(attrs, d.StartPrice, 10)
+		attrs = append(attrs, ',', '"', 'e', 'p', '"', ':')
+		attrs = strconv.AppendUint(attrs, d.EndPrice, 10)
+		attrs = append(attrs, ',', '"', 's', 'b', '"', ':')
+		attrs = strconv.AppendUint(attrs, d.StartBlock, 10)
+		attrs = append(attrs, ',', '"', 'e', 'b', '"', ':')
+		attrs = strconv.AppendUint(attrs, d.EndBlock, 10)
+		attrs = append(attrs, ',', '"', 'a', 's', '"', ':', '"')
+		attrs = append(attrs, d.Asset...)
+		attrs = append(attrs, '"', ',', '"', 'w', 'l', '"', ':')
+		attrs = strconv.AppendUint(attrs, d.WalletLimit, 10)
+	}
+	attrs = append(attrs, '}')
+
+	emitEventJSON(eventType, string(attrs))
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/binary"
 	"strconv"
 	"vsc_nft_mgmt/sdk"
 )
@@ -24,6 +23,7 @@ import (
 //
 // Returns:
 // <owner>|<col>|<tx>|<name>|<desc>|<meta>
+// - owner is the current owner (differs from the ID prefix after col_transfer).
 //
 //go:wasmexport col_get
 func GetCollection(payload *string) *string {
@@ -39,6 +39,7 @@ func GetCollection(payload *string) *string {
 		sdk.Abort("collection not found")
 	}
 	colData := *colDataPtr
+	owner = collectionOwner(ownerCol)
 
 	// Build output: <owner>|<col>|<tx>|<name>|<desc>|<meta>
 	b := make([]byte, 0, len(owner)+len(col)+len(colData)+2)
@@ -90,6 +91,146 @@ func CollectionExists(ownerIndex *string) *string {
 	return &t
 }
 
+// IsCollectionLocked returns "true" if the collection can no longer be updated.
+//
+// Payload: "<owner>_<collectionIndex>"
+//
+//go:wasmexport col_isLocked
+func IsCollectionLocked(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	loadCollection(*payload) // ensures "<owner>_<collection>" exists
+	out := "false"
+	if isCollectionLocked(*payload) {
+		out = "true"
+	}
+	return &out
+}
+
+// GetCollectionSupply returns minted supply and caps of a collection.
+//
+// Payload: "<owner>_<collectionIndex>"
+// Returns: "<mintedNFTs>|<maxNFTs>|<mintedEditions>|<maxEditions>" (max 0 = unlimited)
+// Minted counts include burned NFTs.
+//
+//go:wasmexport col_supply
+func GetCollectionSupply(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	loadCollection(*payload) // ensures "<owner>_<collection>" exists
+	s := collectionSupplyToStr(loadCollectionSupply(*payload))
+	return &s
+}
+
+// GetCollectionMarkets returns the markets that may currently act on NFTs
+// minted into and held in a collection: enabled registry entries scoped to it,
+// filtered by the collection allow/deny list. The list also applies to NFTs
+// minted here wherever they are held.
+//
+// Payload: "<owner>_<collectionIndex>"
+// Returns: comma-separated market addresses, e.g. "contract:a,contract:b"
+//
+//go:wasmexport col_markets
+func GetCollectionMarkets(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	ownerCol := *payload
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+
+	b := make([]byte, 0, 64)
+	if all := GetMarketContractsCSV(nil); all != nil {
+		list := *all
+		start := 0
+		for i := 0; i <= len(list); i++ {
+			if i < len(list) && list[i] != '|' {
+				continue
+			}
+			market := list[start:i]
+			start = i + 1
+			if !isMarketFor(market, ownerCol, ownerCol, marketPermTransfer|marketPermBurn) {
+				continue
+			}
+			if len(b) > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, market...)
+		}
+	}
+	s := string(b)
+	return &s
+}
+
+// GetCollectionMinters returns the delegated minters of a collection.
+//
+// Payload: "<owner>_<collectionIndex>"
+// Returns: '|'-joined minter addresses, e.g. "hive:bob|hive:mintbot"
+// The collection owner is always allowed to mint and is not part of the list.
+//
+//go:wasmexport col_minters
+func GetCollectionMinters(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	loadCollection(*payload) // ensures "<owner>_<collection>" exists
+	ptr := sdk.StateGetObject(colMintersKey(*payload))
+	if ptr == nil {
+		empty := ""
+		return &empty
+	}
+	return ptr
+}
+
+// GetCollectionSize returns how many NFTs currently have at least one token
+// (the unique NFT or one of its editions) in a collection.
+//
+// Payload: "<owner>_<collectionIndex>"
+// Returns: decimal string, e.g. "42"
+//
+//go:wasmexport col_size
+func GetCollectionSize(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	loadCollection(*payload) // ensures "<owner>_<collection>" exists
+	members, _ := collectionMembers(*payload).head()
+	s := strconv.FormatUint(members, 10)
+	return &s
+}
+
+// GetCollectionNFTs lists the NFT IDs inside a collection, one page at a time.
+//
+// Payload: "<owner>_<collectionIndex>|<cursor>|<limit>"
+// - cursor is the number of NFTs already read (empty = 0)
+// - limit defaults to and is capped at maxPageLimit
+//
+// Returns comma-separated NFT IDs, e.g. "3,8,12". The next cursor is
+// cursor + number of returned IDs; fewer than limit IDs means the end was
+// reached. Order follows the on-chain index and may change after transfers.
+//
+//go:wasmexport col_nfts
+func GetCollectionNFTs(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	ownerCol, cursor, limit := parsePage(*payload)
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	set := collectionMembers(ownerCol)
+	members, _ := set.head()
+
+	b := make([]byte, 0, limit*8)
+	for slot := cursor; slot < members && slot < cursor+limit; slot++ {
+		if slot > cursor {
+			b = append(b, ',')
+		}
+		b = strconv.AppendUint(b, set.member(slot), 10)
+	}
+	s := string(b)
+	return &s
+}
+
 // GetNFT returns metadata and current ownership state for an NFT or edition.
 //
 // **Payload formats**
@@ -190,10 +331,11 @@ func GetNFT(payload *string) *string {
 }
 
 // GetNFTOwnedEditions returns a CSV list (e.g. "0,1,2") of editions
-// owned by a specified address.
+// owned by a specified address. Burned editions are never listed.
 //
 // Payload: "<nftID>,<ownerAddress>"
-// If no editions are owned, returns an empty string.
+// If no editions are owned (or the NFT is unique), returns an empty string.
+// Use nft_ownedEditionRanges for a compact answer on large edition counts.
 //
 //go:wasmexport nft_hasNFTEdition
 func GetNFTOwnedEditions(payload *string) *string {
@@ -205,20 +347,66 @@ func GetNFTOwnedEditions(payload *string) *string {
 	id := parseUint64Field(p, 0, comma)
 	owner := p[comma+1:]
 
-	key := ownedIndexKey(id, owner)
-	ptr := sdk.StateGetObject(key)
-	if ptr == nil || *ptr == "" {
-		empty := ""
-		return &empty
+	editions := make([]uint32, 0, 8)
+	if *loadNFTEditionCount(id) > 1 {
+		ranges := loadOwnedRanges(id, owner)
+		for i := 0; i+1 < len(ranges); i += 2 {
+			for ed := ranges[i]; ed < ranges[i+1]; ed++ {
+				editions = append(editions, ed)
+			}
+		}
 	}
+	csv := Uint32ListToCSV(editions)
+	return &csv
+}
 
-	buf := []byte(*ptr)
-	N := len(buf) / 4
-	editions := make([]uint32, 0, N)
-	for i := 0; i+3 < len(buf); i += 4 {
-		editions = append(editions, binary.BigEndian.Uint32(buf[i:i+4]))
+// GetNFTOwnedEditionRanges returns the editions owned by a specified address
+// as a CSV list where consecutive editions are collapsed into inclusive
+// ranges (e.g. "0-99,120,150-199"). Burned editions are never listed.
+// For unique NFTs "0" is returned if the address is the current owner.
+//
+// Payload: "<nftID>,<ownerAddress>"
+// If no editions are owned, returns an empty string.
+//
+//go:wasmexport nft_ownedEditionRanges
+func GetNFTOwnedEditionRanges(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
 	}
-	csv := Uint32ListToCSV(editions)
+	p := *payload
+	comma := split2(p)
+	id := parseUint64Field(p, 0, comma)
+	owner := p[comma+1:]
+
+	empty := ""
+	if *loadNFTEditionCount(id) <= 1 {
+		if eo := loadEditionOverride(id, 0); eo != nil && eo.Burned {
+			return &empty
+		}
+		baseOwner := collectionOwner(*loadNFTOwnerCollection(id))
+		if baseOwner != owner {
+			return &empty
+		}
+		zero := "0"
+		return &zero
+	}
+
+	ranges := loadOwnedRanges(id, owner)
+	if len(ranges) == 0 {
+		return &empty
+	}
+	b := make([]byte, 0, len(ranges)*11)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendUint(b, uint64(ranges[i]), 10)
+		if last := ranges[i+1] - 1; last > ranges[i] {
+			b = append(b, '-')
+			b = strconv.AppendUint(b, uint64(last), 10)
+		}
+	}
+	csv := string(b)
 	return &csv
 }
 
@@ -277,7 +465,7 @@ func IsOwner(payload *string) *string {
 		sdk.Abort("edition required for multi-edition NFT")
 	}
 
-	curOwner, _ := splitOwnerCollection(ownerCol)
+	curOwner := collectionOwner(ownerCol)
 	caller := sdk.GetEnvKey("msg.sender")
 
 	if caller != nil && *caller == curOwner {
@@ -360,7 +548,7 @@ func GetNFTCreatorRaw(id *string) *string {
 // Payload: "<id>"
 // Output: metadata string from core state
 //
-// Metadata is stored as-is from mint; opaque for this contract.
+// Metadata is stored as-is from mint (or the latest nft_setMeta); opaque for this contract.
 //
 //go:wasmexport nft_meta
 func GetNFTMeta(id *string) *string {
@@ -377,6 +565,48 @@ func GetNFTMeta(id *string) *string {
 	return &meta
 }
 
+// GetNFTRoyaltyInfo returns the royalty due on a sale (EIP-2981 style).
+//
+// Payload: "<id>|<salePrice>"
+// Returns: "<recipient>|<amount>" or, for split royalties, one pair per
+// recipient joined by commas: "<recipientA>|<amountA>,<recipientB>|<amountB>"
+// - amount = floor(salePrice * shareBps / 10000), in the same unit as salePrice.
+// - Returns "|0" if the NFT has no royalty.
+//
+//go:wasmexport nft_royaltyInfo
+func GetNFTRoyaltyInfo(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	p := *payload
+	idx := indexByte(p, '|')
+	if idx <= 0 || idx == len(p)-1 {
+		sdk.Abort("invalid payload")
+	}
+	nftID := parseUint64Field(p, 0, idx)
+	price := parseUint64Field(p, idx+1, len(p))
+
+	r := loadNFTRoyalty(nftID)
+	if r == nil {
+		loadNFTOwnerCollection(nftID) // aborts if the nft does not exist
+		none := "|0"
+		return &none
+	}
+	shares := royaltyShares(*r, loadNFTOrigin(nftID))
+
+	b := make([]byte, 0, len(r.Recipient)+len(shares)*32)
+	for i, sh := range shares {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, sh.Recipient...)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, royaltyAmount(price, sh.Bps), 10)
+	}
+	result := string(b)
+	return &result
+}
+
 // GetNFTSupply returns the total edition count for a given NFT.
 //
 // Payload: "<id>"
@@ -480,3 +710,129 @@ func IsNFTSingleTransfer(id *string) *string {
 	f := "false"
 	return &f
 }
+
+// GetBalanceOf returns how many tokens (unique NFTs plus individual editions)
+// an address currently holds across all of its collections.
+//
+// Payload: "<ownerAddress>"
+// Returns: decimal string, e.g. "12"
+//
+//go:wasmexport nft_balanceOf
+func GetBalanceOf(owner *string) *string {
+	if owner == nil || *owner == "" {
+		sdk.Abort("empty owner")
+	}
+	_, tokens := ownerHoldings(*owner).head()
+	s := strconv.FormatUint(tokens, 10)
+	return &s
+}
+
+// GetTokensOf lists the tokens held by an address, one page at a time.
+//
+// Payload: "<ownerAddress>|<cursor>|<limit>"
+// - cursor is the nextCursor of the previous page (empty = first page)
+// - limit defaults to and is capped at maxPageLimit
+//
+// Returns "<nextCursor>|" followed by comma-separated "<id>|<edition>" pairs
+// (edition is 0 for unique NFTs), e.g. "2.7.14|4|0,7|12,7|13". nextCursor is
+// empty once the end was reached. The cursor is opaque to callers; it holds
+// the index slot, the NFT in it and the next edition ("<slot>.<id>.<edition>"),
+// so it stays usable when the token it points to leaves the owner. Order
+// follows the on-chain index and may change after transfers, so tokens moved
+// between pages can be skipped or repeated.
+//
+//go:wasmexport nft_tokensOf
+func GetTokensOf(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	owner := parts[0]
+	if owner == "" {
+		sdk.Abort("empty payload")
+	}
+	limit := parsePageLimit(parts[2])
+	set := ownerHoldings(owner)
+	members, _ := set.head()
+
+	var start uint64
+	var fromEd uint32 // first edition to return of the NFT in the start slot
+	if parts[1] != "" {
+		var cursorID uint64
+		start, cursorID, fromEd = parseTokensCursor(parts[1])
+		if start < members && set.member(start) != cursorID {
+			// the cursor token left the owner and another NFT took its slot
+			fromEd = 0
+		}
+	}
+
+	b := make([]byte, 0, 16+limit*16)
+	b = append(b, '|')
+	var n uint64
+	for slot := start; slot < members; slot++ {
+		id := set.member(slot)
+		if *loadNFTEditionCount(id) <= 1 {
+			if n == limit {
+				return tokensPage(b, slot, id, 0)
+			}
+			b = appendTokenPair(b, n, id, 0)
+			n++
+			continue
+		}
+		ranges := loadOwnedRanges(id, owner)
+		for i := 0; i+1 < len(ranges); i += 2 {
+			ed, end := ranges[i], ranges[i+1]
+			if ed < fromEd {
+				ed = fromEd
+			}
+			for ; ed < end; ed++ {
+				if n == limit {
+					return tokensPage(b, slot, id, ed)
+				}
+				b = appendTokenPair(b, n, id, ed)
+				n++
+			}
+		}
+		fromEd = 0
+	}
+	s := string(b)
+	return &s
+}
+
+// tokensPage prefixes a full nft_tokensOf page (b starts with '|') with the
+// cursor of the next token to read.
+func tokensPage(b []byte, slot uint64, id uint64, ed uint32) *string {
+	c := make([]byte, 0, 48+len(b))
+	c = strconv.AppendUint(c, slot, 10)
+	c = append(c, '.')
+	c = strconv.AppendUint(c, id, 10)
+	c = append(c, '.')
+	c = strconv.AppendUint(c, uint64(ed), 10)
+	c = append(c, b...)
+	s := string(c)
+	return &s
+}
+
+// parseTokensCursor parses a nft_tokensOf cursor "<slot>.<id>.<edition>".
+func parseTokensCursor(c string) (uint64, uint64, uint32) {
+	i := indexByte(c, '.')
+	if i < 0 {
+		sdk.Abort("invalid cursor")
+	}
+	j := indexByte(c[i+1:], '.')
+	if j < 0 {
+		sdk.Abort("invalid cursor")
+	}
+	j += i + 1
+	return parseUint64Field(c, 0, i), parseUint64Field(c, i+1, j), parseUint32Field(c, j+1, len(c))
+}
+
+// appendTokenPair appends "<id>|<edition>", comma-separated after the first pair.
+func appendTokenPair(b []byte, n uint64, id uint64, ed uint32) []byte {
+	if n > 0 {
+		b = append(b, ',')
+	}
+	b = strconv.AppendUint(b, id, 10)
+	b = append(b, '|')
+	return strconv.AppendUint(b, uint64(ed), 10)
+}
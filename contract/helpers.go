@@ -24,7 +24,26 @@ const (
 	kCreator    byte = 0x03 // Creator address + transfer-flag
 	kEdCount    byte = 0x04 // Edition count
 	kEdOverride byte = 0x05 // Edition-specific overrides (owner or burned)
-	kOwnedIdx   byte = 0x06 // Owned edition index for quick lookup
+	kOwnedIdx   byte = 0x06 // Legacy owned edition list (append-only), migrated to kOwnedRng on write
+	kApproval   byte = 0x07 // Per-edition approved address
+	kRoyalty    byte = 0x08 // Creator royalty: "bps|recipient"
+	kOwnedRng   byte = 0x09 // Owned edition ranges per owner address
+	kHoldHead   byte = 0x0A // Per-owner holdings: "<nfts>|<tokens>"
+	kHoldSlot   byte = 0x0B // Per-owner holdings: slot -> NFT ID
+	kHoldPos    byte = 0x0C // Per-owner holdings: NFT ID -> "<slot>|<tokens>"
+	kColHead    byte = 0x0D // Per-collection members: "<nfts>|<tokens>"
+	kColSlot    byte = 0x0E // Per-collection members: slot -> NFT ID
+	kColPos     byte = 0x0F // Per-collection members: NFT ID -> "<slot>|<tokens>"
+	kMetaCtl    byte = 0x10 // Metadata control: "<frozen>|<updater>"
+	kListing    byte = 0x11 // Fixed-price listing per edition: "<seller>|<price>|<asset>"
+	kAuction    byte = 0x12 // Open auction per edition (locks the token)
+	kOffer      byte = 0x13 // Escrowed offer per offer ID
+	kOfferHead  byte = 0x14 // Per-token offers: "<offers>|<offers>"
+	kOfferSlot  byte = 0x15 // Per-token offers: slot -> offer ID
+	kOfferPos   byte = 0x16 // Per-token offers: offer ID -> "<slot>|1"
+	kColOffHead byte = 0x17 // Per-collection offers: "<offers>|<offers>"
+	kColOffSlot byte = 0x18 // Per-collection offers: slot -> offer ID
+	kColOffPos  byte = 0x19 // Per-collection offers: offer ID -> "<slot>|1"
 )
 
 //
@@ -109,9 +128,70 @@ func editionOverrideKey(nftID uint64, editionIndex uint32) string {
 	return string(buf[:])
 }
 
-// ownedIndexKey tracks editions owned by a specific address.
+// approvalKey stores the single address approved to move one edition.
+func approvalKey(nftID uint64, editionIndex uint32) string {
+	var buf [13]byte
+	buf[0] = kApproval
+	packU64LEInline(nftID, buf[1:])
+	packU32LEInline(editionIndex, buf[9:])
+	return string(buf[:])
+}
+
+// listingKey stores the fixed-price listing of an edition.
+func listingKey(nftID uint64, editionIndex uint32) string {
+	var buf [13]byte
+	buf[0] = kListing
+	packU64LEInline(nftID, buf[1:])
+	packU32LEInline(editionIndex, buf[9:])
+	return string(buf[:])
+}
+
+// auctionKey stores the open auction of an edition.
+func auctionKey(nftID uint64, editionIndex uint32) string {
+	var buf [13]byte
+	buf[0] = kAuction
+	packU64LEInline(nftID, buf[1:])
+	packU32LEInline(editionIndex, buf[9:])
+	return string(buf[:])
+}
+
+// offerKey stores an escrowed offer.
+func offerKey(offerID uint64) string {
+	var buf [9]byte
+	buf[0] = kOffer
+	packU64LEInline(offerID, buf[1:])
+	return string(buf[:])
+}
+
+// royaltyKey stores the creator royalty of an NFT.
+func royaltyKey(nftID uint64) string {
+	var buf [9]byte
+	buf[0] = kRoyalty
+	packU64LEInline(nftID, buf[1:])
+	return string(buf[:])
+}
+
+// metaCtlKey stores the metadata freeze flag and updater of an NFT.
+func metaCtlKey(nftID uint64) string {
+	var buf [9]byte
+	buf[0] = kMetaCtl
+	packU64LEInline(nftID, buf[1:])
+	return string(buf[:])
+}
+
+// ownedIndexKey tracks edition ranges owned by a specific address.
 // Uses heap for the owner suffix since length is variable.
 func ownedIndexKey(nftID uint64, owner string) string {
+	b := make([]byte, 0, 1+8+len(owner))
+	b = append(b, kOwnedRng)
+	b = packU64LE(nftID, b)
+	b = append(b, owner...)
+	return string(b)
+}
+
+// legacyOwnedIndexKey is the append-only edition list written before the
+// range index existed. It is only read as a fallback (see loadOwnedRanges).
+func legacyOwnedIndexKey(nftID uint64, owner string) string {
 	b := make([]byte, 0, 1+8+len(owner))
 	b = append(b, kOwnedIdx)
 	b = packU64LE(nftID, b)
@@ -119,6 +199,17 @@ func ownedIndexKey(nftID uint64, owner string) string {
 	return string(b)
 }
 
+// operatorKey returns "op_<scope>|<operator>" where scope is either "<owner>"
+// (all NFTs of an owner) or "<owner>_<collection>" (a single collection).
+func operatorKey(scope, operator string) string {
+	b := make([]byte, 0, 3+len(scope)+1+len(operator))
+	b = append(b, 'o', 'p', '_')
+	b = append(b, scope...)
+	b = append(b, '|')
+	b = append(b, operator...)
+	return string(b)
+}
+
 //
 // ===============
 // Global Counters
@@ -143,6 +234,21 @@ func setNFTCount(n uint64) {
 	sdk.StateSetObject("nft_count", string(buf))
 }
 
+// getOfferCount returns the number of offers ever made (next offer ID).
+func getOfferCount() uint64 {
+	ptr := sdk.StateGetObject("offer_count")
+	if ptr == nil || *ptr == "" {
+		return 0
+	}
+	return parseUint64Field(*ptr, 0, len(*ptr))
+}
+
+func setOfferCount(n uint64) {
+	buf := make([]byte, 0, 20)
+	buf = strconv.AppendUint(buf, n, 10)
+	sdk.StateSetObject("offer_count", string(buf))
+}
+
 // userColCountKey represents the state key for the collection incrementer per user
 func userColCountKey(owner string) string { return "uc_" + owner }
 
@@ -233,6 +339,63 @@ func indexByte(s string, c byte) int {
 	return -1
 }
 
+// countByte returns how often c occurs in s.
+func countByte(s string, c byte) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			n++
+		}
+	}
+	return n
+}
+
+// lastIndexByte returns the last index of c in s or -1 if missing.
+//
+//go:inline
+func lastIndexByte(s string, c byte) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// isValidAccount reports whether addr is a user or contract address that can
+// be stored in '|'/',' delimited records and written into event JSON as is.
+func isValidAccount(addr string) bool {
+	if hasReservedChars(addr) {
+		return false
+	}
+	a := sdk.Address(addr)
+	return a.IsValid() || a.Domain() == sdk.AddressDomainContract
+}
+
+// hasReservedChars reports whether s contains a payload / event delimiter
+// ('|', ',', '"', '\') or a control character.
+func hasReservedChars(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c == '|' || c == ',' || c == '"' || c == '\\' {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNFTRef parses "<id>" or "<id>|<edition>".
+// Returns the NFT id, the edition (0 if omitted) and whether an edition was given.
+func parseNFTRef(p string) (uint64, uint32, bool) {
+	idx := indexByte(p, '|')
+	if idx == -1 {
+		return parseUint64Field(p, 0, len(p)), 0, false
+	}
+	if idx == len(p)-1 {
+		sdk.Abort("invalid edition format")
+	}
+	return parseUint64Field(p, 0, idx), parseUint32Field(p, idx+1, len(p)), true
+}
+
 // splitOwnerCollection expects "<owner>_<collection>" strictly.
 //
 //go:inline
@@ -244,6 +407,14 @@ func splitOwnerCollection(ownerCollection string) (string, string) {
 	return ownerCollection[:idx], ownerCollection[idx+1:]
 }
 
+// parseOptionalUint64 parses a decimal field; empty means 0.
+func parseOptionalUint64(s string) uint64 {
+	if s == "" {
+		return 0
+	}
+	return mustParseUint64(s)
+}
+
 // stringToUint32 safely parses a decimal string pointer into a uint32 pointer.
 func stringToUint32(s *string) *uint32 {
 	v := parseUint64Field(*s, 0, len(*s))
@@ -282,6 +453,26 @@ func splitFixedPipe(s string, n int) []string {
 	return parts
 }
 
+// splitPipeLayout splits s into one of several '|' delimited layouts (field
+// counts in ascending order). The largest layout whose separators are all
+// present in s wins, so optional fields can be added in front of a trailing
+// free-form field (metadata) without breaking shorter payloads.
+func splitPipeLayout(s string, layouts ...int) []string {
+	seps := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '|' {
+			seps++
+		}
+	}
+	n := layouts[0]
+	for _, l := range layouts {
+		if seps >= l-1 {
+			n = l
+		}
+	}
+	return splitFixedPipe(s, n)
+}
+
 // parse4 splits a string of format "a|b|c|d" into 4 parts.
 func parse4(s string) (string, string, string, string) {
 	parts := splitFixedPipe(s, 4)
@@ -310,13 +501,18 @@ func packU64LE(x uint64, b []byte) []byte {
 	return append(b, byte(x), byte(x>>8), byte(x>>16), byte(x>>24), byte(x>>32), byte(x>>40), byte(x>>48), byte(x>>56))
 }
 
-// csv lookup and remover for market contract managment
+// csv lookup and remover for market contract and minter managment
 // containsInCSV checks if target is in csv string without allocations
 func containsInCSV(csv string, target string) bool {
+	return containsInList(csv, '|', target)
+}
+
+// containsInList checks if target is an item of a sep-delimited list.
+func containsInList(list string, sep byte, target string) bool {
 	start := 0
-	for i := 0; i <= len(csv); i++ {
-		if i == len(csv) || csv[i] == '|' {
-			if csv[start:i] == target {
+	for i := 0; i <= len(list); i++ {
+		if i == len(list) || list[i] == sep {
+			if list[start:i] == target {
 				return true
 			}
 			start = i + 1
@@ -325,8 +521,9 @@ func containsInCSV(csv string, target string) bool {
 	return false
 }
 
-// removeFromCSV removes target from csv (returns new csv without trailing comma)
-func removeFromCSV(csv string, target string) string {
+// removeFromCSV removes target from csv (returns new csv without trailing separator)
+// and whether target was found at all.
+func removeFromCSV(csv string, target string) (string, bool) {
 	start := 0
 	found := false
 	b := make([]byte, 0, len(csv))
@@ -344,8 +541,19 @@ func removeFromCSV(csv string, target string) string {
 			start = i + 1
 		}
 	}
-	if !found {
-		sdk.Abort("market not found")
+	return string(b), found
+}
+
+// countCSV returns the number of entries in a '|'-joined csv.
+func countCSV(csv string) int {
+	if csv == "" {
+		return 0
 	}
-	return string(b)
+	n := 1
+	for i := 0; i < len(csv); i++ {
+		if csv[i] == '|' {
+			n++
+		}
+	}
+	return n
 }
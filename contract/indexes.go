@@ -0,0 +1,208 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// ===========================
+// ENUMERABLE NFT ID INDEXES
+// ===========================
+//
+// An idSet is an on-chain, enumerable set of NFT IDs belonging to one scope
+// (an owner address or a collection). Every member carries the number of tokens
+// (editions) it contributes to the scope, so the set can answer both
+// "how many NFTs" and "how many tokens" with a single read.
+//
+// Storage layout (all keys end with the variable-length scope):
+//
+//	[headTag][scope]             → "<members>|<tokens>"
+//	[slotTag][slot u64 LE][scope] → "<nftID>"
+//	[posTag][nftID u64 LE][scope] → "<slot>|<tokens>"
+//
+// Members are kept densely packed in slots 0..members-1; removals move the
+// last member into the freed slot (swap-remove), so enumeration order is not
+// stable across mutations.
+
+type idSet struct {
+	headTag byte
+	slotTag byte
+	posTag  byte
+	scope   string
+}
+
+// ownerHoldings is the set of NFTs an address holds at least one token of.
+func ownerHoldings(owner string) idSet {
+	return idSet{headTag: kHoldHead, slotTag: kHoldSlot, posTag: kHoldPos, scope: owner}
+}
+
+// collectionMembers is the set of NFTs with at least one token in a "<owner>_<collection>".
+func collectionMembers(ownerCol string) idSet {
+	return idSet{headTag: kColHead, slotTag: kColSlot, posTag: kColPos, scope: ownerCol}
+}
+
+func (s idSet) headKey() string {
+	b := make([]byte, 0, 1+len(s.scope))
+	b = append(b, s.headTag)
+	b = append(b, s.scope...)
+	return string(b)
+}
+
+func (s idSet) slotKey(slot uint64) string {
+	b := make([]byte, 0, 9+len(s.scope))
+	b = append(b, s.slotTag)
+	b = packU64LE(slot, b)
+	b = append(b, s.scope...)
+	return string(b)
+}
+
+func (s idSet) posKey(nftID uint64) string {
+	b := make([]byte, 0, 9+len(s.scope))
+	b = append(b, s.posTag)
+	b = packU64LE(nftID, b)
+	b = append(b, s.scope...)
+	return string(b)
+}
+
+// head returns (members, tokens); both are 0 for an empty set.
+func (s idSet) head() (uint64, uint64) {
+	return loadCounterPair(s.headKey())
+}
+
+// member returns the NFT ID stored in slot (slot < members).
+func (s idSet) member(slot uint64) uint64 {
+	ptr := sdk.StateGetObject(s.slotKey(slot))
+	if ptr == nil || *ptr == "" {
+		sdk.Abort("index slot missing")
+	}
+	return mustParseUint64(*ptr)
+}
+
+// slot returns the slot of nftID and whether it is a member.
+func (s idSet) slot(nftID uint64) (uint64, bool) {
+	ptr := sdk.StateGetObject(s.posKey(nftID))
+	if ptr == nil || *ptr == "" {
+		return 0, false
+	}
+	slot, _ := parseCounterPair(*ptr)
+	return slot, true
+}
+
+// tokens returns how many tokens of nftID the scope holds (0 if none).
+func (s idSet) tokens(nftID uint64) uint64 {
+	_, n := loadCounterPair(s.posKey(nftID))
+	return n
+}
+
+// add credits n tokens of nftID to the scope, inserting it if new.
+func (s idSet) add(nftID uint64, n uint64) {
+	members, total := s.head()
+	posKey := s.posKey(nftID)
+	ptr := sdk.StateGetObject(posKey)
+	if ptr == nil || *ptr == "" {
+		sdk.StateSetObject(s.slotKey(members), strconv.FormatUint(nftID, 10))
+		saveCounterPair(posKey, members, n)
+		members++
+	} else {
+		slot, cnt := parseCounterPair(*ptr)
+		saveCounterPair(posKey, slot, cnt+n)
+	}
+	saveCounterPair(s.headKey(), members, total+n)
+}
+
+// remove debits n tokens of nftID, dropping it from the set once none are left.
+func (s idSet) remove(nftID uint64, n uint64) {
+	posKey := s.posKey(nftID)
+	ptr := sdk.StateGetObject(posKey)
+	if ptr == nil || *ptr == "" {
+		return // not indexed (e.g. minted before the index existed)
+	}
+	members, total := s.head()
+	slot, cnt := parseCounterPair(*ptr)
+	if n > cnt {
+		n = cnt
+	}
+	if total >= n {
+		total -= n
+	} else {
+		total = 0
+	}
+	if cnt > n {
+		saveCounterPair(posKey, slot, cnt-n)
+		saveCounterPair(s.headKey(), members, total)
+		return
+	}
+
+	// swap-remove: move the last member into the freed slot
+	last := members - 1
+	if slot != last {
+		lastID := s.member(last)
+		lastPosKey := s.posKey(lastID)
+		_, lastCnt := loadCounterPair(lastPosKey)
+		sdk.StateSetObject(s.slotKey(slot), strconv.FormatUint(lastID, 10))
+		saveCounterPair(lastPosKey, slot, lastCnt)
+	}
+	sdk.StateDeleteObject(s.slotKey(last))
+	sdk.StateDeleteObject(posKey)
+	if last == 0 {
+		sdk.StateDeleteObject(s.headKey())
+		return
+	}
+	saveCounterPair(s.headKey(), last, total)
+}
+
+// =====================
+// Counter Pair Encoding
+// =====================
+
+func loadCounterPair(key string) (uint64, uint64) {
+	ptr := sdk.StateGetObject(key)
+	if ptr == nil || *ptr == "" {
+		return 0, 0
+	}
+	return parseCounterPair(*ptr)
+}
+
+func parseCounterPair(s string) (uint64, uint64) {
+	a, b := split2Str(s)
+	return mustParseUint64(a), mustParseUint64(b)
+}
+
+func saveCounterPair(key string, a, b uint64) {
+	buf := make([]byte, 0, 41)
+	buf = strconv.AppendUint(buf, a, 10)
+	buf = append(buf, '|')
+	buf = strconv.AppendUint(buf, b, 10)
+	sdk.StateSetObject(key, string(buf))
+}
+
+// parsePage reads "<scope>|<cursor>|<limit>"; empty cursor = 0, empty limit = maxPageLimit.
+func parsePage(p string) (string, uint64, uint64) {
+	parts := splitFixedPipe(p, 3)
+	if parts[0] == "" {
+		sdk.Abort("empty payload")
+	}
+	cursor, limit := parseCursorLimit(parts[1], parts[2])
+	return parts[0], cursor, limit
+}
+
+// parseCursorLimit reads the paging fields of parsePage.
+func parseCursorLimit(cursorStr, limitStr string) (uint64, uint64) {
+	var cursor uint64
+	if cursorStr != "" {
+		cursor = mustParseUint64(cursorStr)
+	}
+	return cursor, parsePageLimit(limitStr)
+}
+
+// parsePageLimit reads a page size; empty = maxPageLimit.
+func parsePageLimit(limitStr string) uint64 {
+	limit := uint64(maxPageLimit)
+	if limitStr != "" {
+		limit = mustParseUint64(limitStr)
+		if limit == 0 || limit > maxPageLimit {
+			sdk.Abort("invalid page limit")
+		}
+	}
+	return limit
+}
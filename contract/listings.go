@@ -0,0 +1,235 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// ======================
+// FIXED-PRICE LISTINGS
+// ======================
+//
+// Owners can sell an NFT or a single edition for a fixed amount of HIVE or HBD
+// without an external market contract. Listings are non-custodial: the token
+// stays with the seller until it is bought, and every transfer or burn of the
+// token drops its listing.
+//
+// On purchase the buyer's funds are drawn into the contract via intents
+// (sdk.HiveDraw), creator royalties are paid out and the rest goes to the
+// seller, all in the same call that moves the token.
+//
+// Amounts are integers in the asset's smallest unit (1000 = 1.000 HIVE).
+// A listing is stored under listingKey(nftID, edition) as "<seller>|<price>|<asset>".
+
+type Listing struct {
+	Seller string
+	Price  uint64
+	Asset  sdk.Asset
+}
+
+// CreateListing lists an NFT or edition for sale (replacing an existing listing).
+// Payload format: "<nftID>|<editionIndex>|<price>|<asset>"
+// - editionIndex may be empty for unique NFTs (defaults to 0)
+// - asset is "hive" or "hbd"
+// Only the current owner may list; soulbound NFTs that left their creator
+// cannot be listed. A listing event is emitted.
+//
+//go:wasmexport list_create
+func CreateListing(payload *string) *string {
+	requireNotPaused(pauseTrade)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 4)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	var ed uint32
+	if edStr := parts[1]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	price := parsePrice(parts[2])
+	asset := parseSaleAsset(parts[3])
+
+	ownerCol, effectiveEd, edTotal := loadTokenOwnerCollection(id, ed)
+	requireNotInAuction(id, effectiveEd)
+	seller := collectionOwner(ownerCol)
+	caller := sdk.GetEnvKey("msg.caller")
+	if caller == nil || *caller != seller {
+		sdk.Abort("only owner can list")
+	}
+	requireUnbound(id, seller)
+
+	l := Listing{Seller: seller, Price: price, Asset: asset}
+	sdk.StateSetObject(listingKey(id, effectiveEd), listingToStr(l))
+	emitListing("listing", id, editionRef(effectiveEd, edTotal), l)
+	return nil
+}
+
+// CancelListing removes the listing of an NFT or edition.
+// Payload formats:
+//
+//	"<nftID>"           → unique NFT
+//	"<nftID>|<edition>" → specific edition
+//
+// Only the seller may cancel. Cancelling keeps working while trading is paused.
+// A listingCancel event is emitted.
+//
+//go:wasmexport list_cancel
+func CancelListing(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	id, ed, _ := parseNFTRef(*payload)
+	edTotal := *loadNFTEditionCount(id)
+	l := loadListing(id, ed)
+	if l == nil {
+		sdk.Abort("listing not found")
+	}
+	caller := sdk.GetEnvKey("msg.caller")
+	if caller == nil || *caller != l.Seller {
+		sdk.Abort("only seller can cancel listing")
+	}
+
+	sdk.StateDeleteObject(listingKey(id, ed))
+	emitListing("listingCancel", id, editionRef(ed, edTotal), *l)
+	return nil
+}
+
+// BuyListing buys a listed NFT or edition into one of the caller's collections.
+// Payload format: "<nftID>|<editionIndex>|<owner>_<collection>"
+// The caller must provide a HIVE/HBD intent covering the listing price.
+// The price is drawn, royalties and the seller are paid and the token is
+// moved, emitting royalty-aware sale and transfer events.
+//
+//go:wasmexport list_buy
+func BuyListing(payload *string) *string {
+	requireNotPaused(pauseTrade | pauseTransfer)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	var ed uint32
+	if edStr := parts[1]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	target := parts[2]
+
+	ownerCol, effectiveEd, edTotal := loadTokenOwnerCollection(id, ed)
+	l := loadListing(id, effectiveEd)
+	if l == nil {
+		sdk.Abort("listing not found")
+	}
+	if collectionOwner(ownerCol) != l.Seller {
+		sdk.Abort("listing is stale") // safety net; transfers drop listings
+	}
+	buyer := *sdk.GetEnvKey("msg.caller")
+	loadCollection(target) // make sure the collection exists
+	if collectionOwner(target) != buyer {
+		sdk.Abort("target collection not owned by buyer")
+	}
+	if buyer == l.Seller {
+		sdk.Abort("cannot buy own listing")
+	}
+	requireUnbound(id, l.Seller)
+
+	sdk.HiveDraw(int64(l.Price), l.Asset)
+	royalty := payoutSale(id, l.Price, l.Asset, l.Seller)
+	moveToken(id, effectiveEd, edTotal, ownerCol, target)
+	emitSale(id, editionRef(effectiveEd, edTotal), l.Seller, buyer, l.Price, l.Asset, royalty)
+	return nil
+}
+
+// GetListing returns the active listing of an NFT or edition.
+//
+// Payload formats: "<nftID>" or "<nftID>|<edition>"
+//
+// Returns: "<seller>|<price>|<asset>" or an empty string if not listed.
+//
+//go:wasmexport list_get
+func GetListing(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	id, ed, _ := parseNFTRef(*payload)
+	out := ""
+	if l := loadListing(id, ed); l != nil {
+		out = listingToStr(*l)
+	}
+	return &out
+}
+
+// ================
+// Sale Settlement
+// ================
+
+// payoutSale distributes price (already held by the contract) to the royalty
+// recipients of the NFT and the remainder to seller. Returns the royalty paid.
+func payoutSale(id uint64, price uint64, asset sdk.Asset, seller string) uint64 {
+	var royalty uint64
+	if r := loadNFTRoyalty(id); r != nil && r.Bps > 0 {
+		for _, sh := range royaltyShares(*r, loadNFTOrigin(id)) {
+			amount := royaltyAmount(price, sh.Bps)
+			if amount == 0 || sh.Recipient == "" {
+				continue
+			}
+			sdk.HiveTransfer(sdk.Address(sh.Recipient), int64(amount), asset)
+			royalty += amount
+		}
+	}
+	if rest := price - royalty; rest > 0 {
+		sdk.HiveTransfer(sdk.Address(seller), int64(rest), asset)
+	}
+	return royalty
+}
+
+// parsePrice parses a positive amount that fits into the SDK's int64 amounts.
+func parsePrice(s string) uint64 {
+	if s == "" {
+		sdk.Abort("price required")
+	}
+	price := mustParseUint64(s)
+	if price == 0 || price > 1<<63-1 {
+		sdk.Abort("invalid price")
+	}
+	return price
+}
+
+func parseSaleAsset(s string) sdk.Asset {
+	switch sdk.Asset(s) {
+	case sdk.AssetHive, sdk.AssetHbd:
+		return sdk.Asset(s)
+	}
+	sdk.Abort("asset must be hive or hbd")
+	return ""
+}
+
+// ================
+// Listing Storage
+// ================
+
+func loadListing(nftID uint64, editionIndex uint32) *Listing {
+	ptr := sdk.StateGetObject(listingKey(nftID, editionIndex))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	parts := splitFixedPipe(*ptr, 3)
+	return &Listing{Seller: parts[0], Price: mustParseUint64(parts[1]), Asset: sdk.Asset(parts[2])}
+}
+
+func listingToStr(l Listing) string {
+	b := make([]byte, 0, len(l.Seller)+28)
+	b = append(b, l.Seller...)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, l.Price, 10)
+	b = append(b, '|')
+	b = append(b, l.Asset...)
+	return string(b)
+}
+
+// clearListing drops the listing of the given edition (no-op if none is set).
+func clearListing(nftID uint64, editionIndex uint32) {
+	key := listingKey(nftID, editionIndex)
+	if ptr := sdk.StateGetObject(key); ptr != nil && *ptr != "" {
+		sdk.StateDeleteObject(key)
+	}
+}
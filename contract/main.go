@@ -7,9 +7,22 @@ package main
 // This file holds core constants and lightweight utility functions
 
 const (
-	maxNameLength = 48                   // upper bound for NFT or collection names
-	maxDescLength = 128                  // max allowed chars for description field
-	contractOwner = "hive:contractowner" // contractOwner can add/remove supported market contract
+	maxNameLength    = 48                   // upper bound for NFT or collection names
+	maxDescLength    = 128                  // max allowed chars for description field
+	maxMinters       = 16                   // max delegated minters per collection
+	maxRoyaltyBps    = 5000                 // max creator royalty in basis points (50%)
+	maxRoyaltySplits = 5                    // max recipients of a split royalty
+	maxMintBatch     = 100                  // max NFTs per nft_mintBatch call
+	maxTransferBatch = 100                  // max items per nft_transferBatch call
+	maxEditionRange  = 1000                 // max editions per nft_transferEditions call
+	maxPageLimit     = 100                  // max entries per page of paginated getters
+	maxMarketCols    = 16                   // max collections a market can be scoped to
+	maxColMarkets    = 16                   // max markets in a collection allow/deny list
+	maxAuctionBlocks = 864000               // max auction duration in blocks (~30 days at 3s)
+	maxDropBlocks    = 864000               // max price decay window of a drop in blocks
+	maxOfferBlocks   = 864000               // max lifetime of an offer in blocks
+	maxOfferItems    = 1000                 // max items wanted by one collection offer
+	contractOwner    = "hive:contractowner" // initial admin until an admin handover is accepted (see admin.go)
 )
 
 func main() {
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// ================
+// MARKET REGISTRY
+// ================
+//
+// Market contracts may move (and optionally burn) NFTs on behalf of their
+// owners. Every registered market carries its own record:
+//
+//	"mk_<address>" → "<slot>|<enabled 0|1>|<permissions>|<collections>|<label>"
+//
+// - permissions is a bitmask of marketPermTransfer / marketPermBurn
+// - collections is a comma-separated list of "<owner>_<collection>" the
+//   market is limited to (empty = every collection)
+// - label is free-form and may contain '|'
+//
+// Markets are enumerable through dense slots ("mkn" → count,
+// "mks_<slot>" → address) that are swap-removed like an idSet.
+//
+// Deployments from before the registry kept a '|'-joined address list
+// under "mc"; it is migrated into the registry on first access, with every
+// entry enabled for transfers on all collections (the old behaviour).
+
+const (
+	legacyMarketKey = "mc"
+	marketCountKey  = "mkn"
+)
+
+const (
+	marketPermTransfer uint64 = 1 << iota // nft_transfer, nft_transferBatch, nft_transferEditions
+	marketPermBurn                        // nft_burn
+)
+
+// Market is the registry entry of a market contract.
+type Market struct {
+	Slot        uint64
+	Enabled     bool
+	Permissions uint64
+	Collections string // comma-separated "<owner>_<collection>", empty = all
+	Label       string
+}
+
+// AddMarketContract registers a market contract or updates its settings.
+//
+// Payload formats:
+//
+//	"<address>"                                                → enabled, transfer only, all collections
+//	"<address>|<enabled>|<permissions>|<collections>|<label>" → full settings
+//
+// - enabled is "true" or "false" (empty = true)
+// - permissions is "transfer", "burn" or "transfer,burn" (empty = transfer)
+// - collections is a comma-separated list of "<owner>_<collection>" (empty = all)
+// - the address must not contain '|', ',', '"' or '\'; the label must not contain '|'
+//
+// Re-adding a registered market with the short payload is a no-op.
+// Only MARKET_MANAGER holders may call this. A market event is emitted.
+//
+//go:wasmexport add_market
+func AddMarketContract(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("market address required")
+	}
+	requireRole(roleMarketManager)
+	migrateLegacyMarkets()
+
+	var parts []string
+	switch countByte(*payload, '|') {
+	case 0:
+		parts = []string{*payload}
+	case 4:
+		parts = splitFixedPipe(*payload, 5)
+	default:
+		sdk.Abort("invalid market payload")
+	}
+	addr := parts[0]
+	if addr == "" {
+		sdk.Abort("market address required")
+	}
+	if hasReservedChars(addr) {
+		sdk.Abort("invalid market address")
+	}
+	existing := loadMarket(addr)
+	m := Market{Enabled: true, Permissions: marketPermTransfer}
+	if len(parts) == 1 {
+		if existing != nil {
+			return nil
+		}
+	} else {
+		switch parts[1] {
+		case "", "true":
+		case "false":
+			m.Enabled = false
+		default:
+			sdk.Abort("enabled must be true or false")
+		}
+		if parts[2] != "" {
+			m.Permissions = parseMarketPermissions(parts[2])
+		}
+		m.Collections = parts[3]
+		validateMarketCollections(m.Collections)
+		m.Label = parts[4]
+		if len(m.Label) > maxNameLength {
+			sdk.Abort("label too long")
+		}
+	}
+
+	if existing != nil {
+		m.Slot = existing.Slot
+	} else {
+		m.Slot = appendMarketSlot(addr)
+	}
+	saveMarket(addr, m)
+	emitMarket(addr, true)
+	return nil
+}
+
+// RemoveMarketContract removes a market contract from the registry.
+// Payload: "<address>"
+// Only MARKET_MANAGER holders may call this. A market event is emitted.
+//
+//go:wasmexport remove_market
+func RemoveMarketContract(addr *string) *string {
+	if addr == nil || *addr == "" {
+		sdk.Abort("market address required")
+	}
+	requireRole(roleMarketManager)
+	migrateLegacyMarkets()
+
+	count := loadMarketCount()
+	if count == 0 {
+		sdk.Abort("no marketplaces found")
+	}
+	m := loadMarket(*addr)
+	if m == nil {
+		sdk.Abort("market not found")
+	}
+
+	// swap-remove: move the last market into the freed slot
+	last := count - 1
+	if m.Slot != last {
+		lastAddr := loadMarketAt(last)
+		lm := loadMarket(lastAddr)
+		lm.Slot = m.Slot
+		saveMarket(lastAddr, *lm)
+		sdk.StateSetObject(marketSlotKey(m.Slot), lastAddr)
+	}
+	sdk.StateDeleteObject(marketSlotKey(last))
+	sdk.StateDeleteObject(marketKey(*addr))
+	saveMarketCount(last)
+
+	emitMarket(*addr, false)
+	return nil
+}
+
+// GetMarketContractsCSV returns all registered market addresses (enabled or not)
+// as a '|'-joined list, or nothing if no market is registered.
+//
+//go:wasmexport get_markets
+func GetMarketContractsCSV(_ *string) *string {
+	if legacy := sdk.StateGetObject(legacyMarketKey); legacy != nil && *legacy != "" {
+		return legacy // not migrated yet; same format
+	}
+	count := loadMarketCount()
+	if count == 0 {
+		return nil
+	}
+	b := make([]byte, 0, count*24)
+	for slot := uint64(0); slot < count; slot++ {
+		if slot > 0 {
+			b = append(b, '|')
+		}
+		b = append(b, loadMarketAt(slot)...)
+	}
+	s := string(b)
+	return &s
+}
+
+// GetMarket returns the registry entry of a market.
+//
+// Payload: "<address>"
+//
+// Returns: "<enabled>|<permissions>|<collections>|<label>",
+// e.g. "true|transfer,burn|hive:alice_0,hive:bob_2|Main Market"
+//
+//go:wasmexport market_get
+func GetMarket(addr *string) *string {
+	if addr == nil || *addr == "" {
+		sdk.Abort("market address required")
+	}
+	m := findMarket(*addr)
+	if m == nil {
+		sdk.Abort("market not found")
+	}
+	b := make([]byte, 0, 32+len(m.Collections)+len(m.Label))
+	b = strconv.AppendBool(b, m.Enabled)
+	b = append(b, '|')
+	b = appendMarketPermissions(b, m.Permissions)
+	b = append(b, '|')
+	b = append(b, m.Collections...)
+	b = append(b, '|')
+	b = append(b, m.Label...)
+	s := string(b)
+	return &s
+}
+
+// ListMarkets lists registered market addresses, one page at a time.
+//
+// Payload: "<cursor>|<limit>" (both optional; empty payload = first page)
+// - cursor is the number of markets already read (empty = 0)
+// - limit defaults to and is capped at maxPageLimit
+//
+// Returns comma-separated addresses. Order may change after removals.
+//
+//go:wasmexport market_list
+func ListMarkets(payload *string) *string {
+	var cursor uint64
+	limit := uint64(maxPageLimit)
+	if payload != nil && *payload != "" {
+		parts := splitPipeLayout(*payload, 1, 2)
+		if parts[0] != "" {
+			cursor = mustParseUint64(parts[0])
+		}
+		if len(parts) == 2 && parts[1] != "" {
+			limit = mustParseUint64(parts[1])
+			if limit == 0 || limit > maxPageLimit {
+				sdk.Abort("invalid page limit")
+			}
+		}
+	}
+
+	b := make([]byte, 0, limit*24)
+	if legacy := sdk.StateGetObject(legacyMarketKey); legacy != nil && *legacy != "" {
+		// not migrated yet: page through the '|'-joined list
+		list := *legacy
+		var slot uint64
+		start := 0
+		for i := 0; i <= len(list) && slot < cursor+limit; i++ {
+			if i < len(list) && list[i] != '|' {
+				continue
+			}
+			if slot >= cursor {
+				if slot > cursor {
+					b = append(b, ',')
+				}
+				b = append(b, list[start:i]...)
+			}
+			slot++
+			start = i + 1
+		}
+	} else {
+		count := loadMarketCount()
+		for slot := cursor; slot < count && slot < cursor+limit; slot++ {
+			if slot > cursor {
+				b = append(b, ',')
+			}
+			b = append(b, loadMarketAt(slot)...)
+		}
+	}
+	s := string(b)
+	return &s
+}
+
+// ================
+// Internal Helpers
+// ================
+
+// isMarketFor reports whether caller is an enabled market holding perm for
+// NFTs currently held in ownerCol, honouring the market policy of policyCol
+// (see marketPolicyCollection).
+func isMarketFor(caller string, ownerCol string, policyCol string, perm uint64) bool {
+	return marketAllows(findMarket(caller), caller, ownerCol, policyCol, perm)
+}
+
+// marketAllows is isMarketFor for a market entry the caller already looked up
+// with findMarket (nil if caller is no market), so loops over many tokens
+// read the registry once.
+func marketAllows(m *Market, caller string, ownerCol string, policyCol string, perm uint64) bool {
+	if m == nil || !m.Enabled || m.Permissions&perm == 0 {
+		return false
+	}
+	if m.Collections != "" && !containsInList(m.Collections, ',', ownerCol) {
+		return false
+	}
+	return collectionAllowsMarket(policyCol, caller)
+}
+
+// marketPolicyCollection returns the collection whose market policy governs
+// an NFT: the collection it was minted into, so a creator's policy follows the
+// NFT to other owners. NFTs minted before origins were tracked fall back to
+// the collection currently holding them.
+func marketPolicyCollection(nftID uint64, ownerCol string) string {
+	if origin := loadNFTOrigin(nftID); origin != "" {
+		return origin
+	}
+	return ownerCol
+}
+
+// findMarket loads a market, reading not yet migrated legacy data without writing.
+func findMarket(addr string) *Market {
+	if legacy := sdk.StateGetObject(legacyMarketKey); legacy != nil && *legacy != "" {
+		if containsInCSV(*legacy, addr) {
+			return &Market{Enabled: true, Permissions: marketPermTransfer}
+		}
+		return nil
+	}
+	return loadMarket(addr)
+}
+
+// migrateLegacyMarkets moves the pre-registry "mc" list into the registry.
+func migrateLegacyMarkets() {
+	legacy := sdk.StateGetObject(legacyMarketKey)
+	if legacy == nil {
+		return
+	}
+	list := *legacy
+	start := 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) && list[i] != '|' {
+			continue
+		}
+		addr := list[start:i]
+		start = i + 1
+		if addr == "" || loadMarket(addr) != nil {
+			continue
+		}
+		m := Market{Enabled: true, Permissions: marketPermTransfer}
+		m.Slot = appendMarketSlot(addr)
+		saveMarket(addr, m)
+	}
+	sdk.StateDeleteObject(legacyMarketKey)
+}
+
+func loadMarket(addr string) *Market {
+	ptr := sdk.StateGetObject(marketKey(addr))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	parts := splitFixedPipe(*ptr, 5)
+	return &Market{
+		Slot:        mustParseUint64(parts[0]),
+		Enabled:     parts[1] == "1",
+		Permissions: mustParseUint64(parts[2]),
+		Collections: parts[3],
+		Label:       parts[4],
+	}
+}
+
+func saveMarket(addr string, m Market) {
+	b := make([]byte, 0, 32+len(m.Collections)+len(m.Label))
+	b = strconv.AppendUint(b, m.Slot, 10)
+	b = append(b, '|')
+	if m.Enabled {
+		b = append(b, '1')
+	} else {
+		b = append(b, '0')
+	}
+	b = append(b, '|')
+	b = strconv.AppendUint(b, m.Permissions, 10)
+	b = append(b, '|')
+	b = append(b, m.Collections...)
+	b = append(b, '|')
+	b = append(b, m.Label...)
+	sdk.StateSetObject(marketKey(addr), string(b))
+}
+
+// appendMarketSlot stores addr in the next free slot and returns the slot.
+func appendMarketSlot(addr string) uint64 {
+	slot := loadMarketCount()
+	sdk.StateSetObject(marketSlotKey(slot), addr)
+	saveMarketCount(slot + 1)
+	return slot
+}
+
+func loadMarketAt(slot uint64) string {
+	ptr := sdk.StateGetObject(marketSlotKey(slot))
+	if ptr == nil || *ptr == "" {
+		sdk.Abort("market slot missing")
+	}
+	return *ptr
+}
+
+func loadMarketCount() uint64 {
+	ptr := sdk.StateGetObject(marketCountKey)
+	if ptr == nil || *ptr == "" {
+		return 0
+	}
+	return mustParseUint64(*ptr)
+}
+
+func saveMarketCount(n uint64) {
+	if n == 0 {
+		sdk.StateDeleteObject(marketCountKey)
+		return
+	}
+	sdk.StateSetObject(marketCountKey, strconv.FormatUint(n, 10))
+}
+
+func parseMarketPermissions(s string) uint64 {
+	var perms uint64
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] != ',' {
+			continue
+		}
+		switch s[start:i] {
+		case "transfer":
+			perms |= marketPermTransfer
+		case "burn":
+			perms |= marketPermBurn
+		default:
+			sdk.Abort("unknown market permission")
+		}
+		start = i + 1
+	}
+	return perms
+}
+
+func appendMarketPermissions(b []byte, perms uint64) []byte {
+	first := true
+	if perms&marketPermTransfer != 0 {
+		b = append(b, "transfer"...)
+		first = false
+	}
+	if perms&marketPermBurn != 0 {
+		if !first {
+			b = append(b, ',')
+		}
+		b = append(b, "burn"...)
+	}
+	return b
+}
+
+// validateMarketCollections ensures every listed collection exists.
+func validateMarketCollections(cols string) {
+	if cols == "" {
+		return
+	}
+	n := 0
+	start := 0
+	for i := 0; i <= len(cols); i++ {
+		if i < len(cols) && cols[i] != ',' {
+			continue
+		}
+		n++
+		if n > maxMarketCols {
+			sdk.Abort("too many market collections")
+		}
+		loadCollection(cols[start:i])
+		start = i + 1
+	}
+}
+
+func marketKey(addr string) string {
+	return "mk_" + addr
+}
+
+func marketSlotKey(slot uint64) string {
+	return "mks_" + strconv.FormatUint(slot, 10)
+}
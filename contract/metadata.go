@@ -0,0 +1,179 @@
+package main
+
+import (
+	"vsc_nft_mgmt/sdk"
+)
+
+// =====================
+// MUTABLE NFT METADATA
+// =====================
+//
+// NFT metadata can be replaced after mint (game items, evolving art) by the
+// creator or by a metadata updater the creator designates. Freezing is
+// one-way: once frozen, metadata and updater can never change again.
+// METADATA_ORACLE holders (see roles.go) may update, but not freeze, the
+// metadata of any NFT.
+// Name, description and the mint tx stay untouched.
+//
+// The control record lives under metaCtlKey(nftID) as "<frozen 0|1>|<updater>".
+
+// SetMeta replaces the metadata of an NFT.
+// Payload format: "<nftID>|<metadata>" (metadata may contain '|')
+// Only the creator, the designated metadata updater or a METADATA_ORACLE may
+// call it, and only while the metadata is not frozen. A metadata event is emitted.
+//
+//go:wasmexport nft_setMeta
+func SetMeta(payload *string) *string {
+	requireNotPaused(pauseMetadata)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	p := *payload
+	sep := indexByte(p, '|')
+	if sep <= 0 {
+		sdk.Abort("invalid payload")
+	}
+	nftID := parseUint64Field(p, 0, sep)
+	meta := p[sep+1:]
+
+	caller := requireMetaEditor(nftID, true)
+
+	core := sdk.StateGetObject(nftCoreKey(nftID))
+	if core == nil || *core == "" {
+		sdk.Abort("nft not found")
+	}
+	tx, name, desc, _ := parse4(*core)
+	b := make([]byte, 0, len(tx)+1+len(name)+1+len(desc)+1+len(meta))
+	b = append(b, tx...)
+	b = append(b, '|')
+	b = append(b, name...)
+	b = append(b, '|')
+	b = append(b, desc...)
+	b = append(b, '|')
+	b = append(b, meta...)
+	sdk.StateSetObject(nftCoreKey(nftID), string(b))
+
+	emitMetadata(nftID, caller, false, nil)
+	return nil
+}
+
+// SetMetaUpdater designates the address allowed to update an NFT's metadata
+// besides the creator.
+// Payload format: "<nftID>|<updaterAddress>" (empty address removes the updater)
+// Only the creator may call it, and only while the metadata is not frozen.
+// A metadata event carrying the new updater ("up") is emitted.
+//
+//go:wasmexport nft_setMetaUpdater
+func SetMetaUpdater(payload *string) *string {
+	requireNotPaused(pauseMetadata)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 2)
+	nftID := parseUint64Field(parts[0], 0, len(parts[0]))
+	updater := parts[1]
+
+	creator, _ := loadNFTCreator(nftID)
+	caller := *sdk.GetEnvKey("msg.sender")
+	if caller != *creator {
+		sdk.Abort("only creator can set metadata updater")
+	}
+	frozen, _ := loadMetaControl(nftID)
+	if frozen {
+		sdk.Abort("metadata is frozen")
+	}
+	if updater != "" && !isValidAccount(updater) {
+		sdk.Abort("invalid updater address")
+	}
+	if updater != "" && updater == *creator {
+		sdk.Abort("creator is always allowed")
+	}
+
+	saveMetaControl(nftID, false, updater)
+	emitMetadata(nftID, caller, false, &updater)
+	return nil
+}
+
+// FreezeMeta permanently locks the metadata of an NFT.
+// Payload format: "<nftID>"
+// Callable by the creator or the metadata updater. A metadata event with "fz":true is emitted.
+//
+//go:wasmexport nft_freezeMeta
+func FreezeMeta(payload *string) *string {
+	requireNotPaused(pauseMetadata)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty id")
+	}
+	nftID := parseUint64Field(*payload, 0, len(*payload))
+
+	caller := requireMetaEditor(nftID, false)
+	saveMetaControl(nftID, true, "") // updater is irrelevant once frozen
+	emitMetadata(nftID, caller, true, nil)
+	return nil
+}
+
+// IsMetaFrozen returns "true" if the NFT metadata can no longer change.
+//
+// Payload: "<nftID>"
+//
+//go:wasmexport nft_isMetaFrozen
+func IsMetaFrozen(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty id")
+	}
+	nftID := parseUint64Field(*payload, 0, len(*payload))
+	loadNFTCreator(nftID) // ensures the NFT exists
+
+	frozen, _ := loadMetaControl(nftID)
+	out := "false"
+	if frozen {
+		out = "true"
+	}
+	return &out
+}
+
+// ================
+// Internal Helpers
+// ================
+
+// requireMetaEditor aborts unless msg.sender may change the NFT metadata
+// (creator, updater or - if allowOracle - a METADATA_ORACLE) and the metadata
+// is not frozen. Returns the caller.
+func requireMetaEditor(nftID uint64, allowOracle bool) string {
+	creator, _ := loadNFTCreator(nftID)
+	frozen, updater := loadMetaControl(nftID)
+	if frozen {
+		sdk.Abort("metadata is frozen")
+	}
+	caller := *sdk.GetEnvKey("msg.sender")
+	if caller != *creator && (updater == "" || caller != updater) &&
+		!(allowOracle && hasRole(roleMetadataOracle, caller)) {
+		sdk.Abort("only creator or metadata updater can change metadata")
+	}
+	if eo := loadEditionOverride(nftID, 0); eo != nil && eo.Burned && *loadNFTEditionCount(nftID) <= 1 {
+		sdk.Abort("nft is burned")
+	}
+	return caller
+}
+
+// loadMetaControl returns (frozen, updater); defaults to (false, "").
+func loadMetaControl(nftID uint64) (bool, string) {
+	ptr := sdk.StateGetObject(metaCtlKey(nftID))
+	if ptr == nil || *ptr == "" {
+		return false, ""
+	}
+	flag, updater := split2Str(*ptr)
+	return flag == "1", updater
+}
+
+func saveMetaControl(nftID uint64, frozen bool, updater string) {
+	b := make([]byte, 0, 2+len(updater))
+	if frozen {
+		b = append(b, '1')
+	} else {
+		b = append(b, '0')
+	}
+	b = append(b, '|')
+	b = append(b, updater...)
+	sdk.StateSetObject(metaCtlKey(nftID), string(b))
+}
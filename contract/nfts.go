@@ -18,54 +18,198 @@ import (
 //
 
 // Mint issues a new NFT under an existing collection.
+// Only the collection owner or one of its delegated minters may mint.
 // Payload format: "<owner>_<collection>|<name>|<desc>|<singleTransfer>|<editions>|<metadata>"
 // - singleTransfer="true" means NFT is non-transferable away from the 2nd owner (minter=1st owner) (soulbound-like)
-// - editions defaults to 1 if the field is empty
+// - editions defaults to 1 if the field is empty or "0"
+// - the collection default royalty (if any) applies; see nft_mintRoyalty for an explicit one
 // After state writes, a mint event is emmited.
 //
 //go:wasmexport nft_mint
 func Mint(payload *string) *string {
+	requireNotPaused(pauseMint)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 6)
+	mintOne(parts[0], parseMintArgs(parts[1:]))
+	return nil
+}
+
+// MintWithRoyalty issues a new NFT with an explicit creator royalty.
+// Payload format: "<owner>_<collection>|<name>|<desc>|<singleTransfer>|<editions>|<royaltyBps>|<royaltyRecipient>|<metadata>"
+// - all other fields and rules are the same as for nft_mint
+// - an empty royaltyRecipient pays the collection owner
+// - empty or 0 royaltyBps falls back to the collection default royalty (if any)
+//
+//go:wasmexport nft_mintRoyalty
+func MintWithRoyalty(payload *string) *string {
+	requireNotPaused(pauseMint)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 8)
+	mintOne(parts[0], parseMintArgs(parts[1:]))
+	return nil
+}
+
+// MintBatch issues several NFTs into one existing collection in a single call.
+// Payload format (records separated by a newline):
+//
+//	"<owner>_<collection>\n<name>|<desc>|<singleTransfer>|<editions>|<royaltyBps>|<royaltyRecipient>|<metadata>\n..."
+//
+// Every record carries the same fields as nft_mintRoyalty; leave royaltyBps
+// and royaltyRecipient empty for the collection default. All records are
+// validated before anything is written; IDs are assigned contiguously and
+// the NFT counter is written once. One mint event is emitted per NFT.
+//
+//go:wasmexport nft_mintBatch
+func MintBatch(payload *string) *string {
+	requireNotPaused(pauseMint)
 	if payload == nil || *payload == "" {
 		sdk.Abort("empty payload")
 	}
 	p := *payload
-	parts := splitFixedPipe(p, 6)
+	nl := indexByte(p, '\n')
+	if nl < 0 {
+		sdk.Abort("no nfts to mint")
+	}
+	ownerCol := p[:nl]
+	if ownerCol == "" {
+		sdk.Abort("collection is mandatory")
+	}
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	creator := requireMinter(ownerCol)
+
+	// Parse and validate all records up front
+	batch := make([]mintArgs, 0, 8)
+	var editions uint64
+	rest := p[nl+1:]
+	for len(rest) > 0 {
+		rec := rest
+		if i := indexByte(rest, '\n'); i >= 0 {
+			rec, rest = rest[:i], rest[i+1:]
+		} else {
+			rest = ""
+		}
+		if rec == "" {
+			continue // tolerate blank lines (e.g. trailing newline)
+		}
+		if len(batch) == maxMintBatch {
+			sdk.Abort("batch too large")
+		}
+		args := parseMintArgs(splitFixedPipe(rec, 7))
+		validateMintArgs(args.name, args.desc)
+		batch = append(batch, args)
+		editions += uint64(args.editions)
+	}
+	if len(batch) == 0 {
+		sdk.Abort("no nfts to mint")
+	}
 
-	ownerCol := parts[0]
-	name := parts[1]
-	desc := parts[2]
-	single := parts[3] == "true"
+	colRoyalty := loadCollectionRoyalty(ownerCol)
+	reserveCollectionSupply(ownerCol, uint64(len(batch)), editions)
+	firstID := getNFTCount()
+	for i, args := range batch {
+		mintNFT(firstID+uint64(i), ownerCol, creator, args, colRoyalty)
+	}
+	setNFTCount(firstID + uint64(len(batch)))
+	return nil
+}
 
-	// editions: empty -> 1 (fast path: parse directly from parts[4])
-	var editions uint32 = 1
-	if edStr := parts[4]; len(edStr) > 0 {
-		editions = parseUint32Field(edStr, 0, len(edStr))
+// ======================
+// Shared Mint Code Path
+// ======================
+
+// mintArgs holds the per-NFT fields of a mint request.
+type mintArgs struct {
+	name     string
+	desc     string
+	meta     string
+	single   bool
+	editions uint32
+	royalty  *Royalty // nil = collection default (if any)
+}
+
+// parseMintArgs reads "<name>|<desc>|<singleTransfer>|<editions>|<metadata>"
+// or "<name>|<desc>|<singleTransfer>|<editions>|<royaltyBps>|<royaltyRecipient>|<metadata>",
+// already split with the fixed layout of the calling export.
+// editions defaults to 1 if the field is empty or "0".
+func parseMintArgs(parts []string) mintArgs {
+	args := mintArgs{
+		name:     parts[0],
+		desc:     parts[1],
+		single:   parts[2] == "true",
+		editions: 1,
+		meta:     parts[len(parts)-1],
+	}
+	if edStr := parts[3]; len(edStr) > 0 {
+		args.editions = parseUint32Field(edStr, 0, len(edStr))
+		if args.editions == 0 {
+			args.editions = 1 // "0" has always meant a unique NFT
+		}
 	}
+	if len(parts) == 7 {
+		args.royalty = parseRoyaltyArgs(parts[4], parts[5])
+	}
+	return args
+}
 
-	meta := parts[5]
+// mintOne validates and mints a single NFT into ownerCol for msg.sender.
+func mintOne(ownerCol string, args mintArgs) {
 	if ownerCol == "" {
 		sdk.Abort("collection is mandatory")
 	}
 
 	// Validate arguments and collection existence
 	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
-	validateMintArgs(name, desc)
+	validateMintArgs(args.name, args.desc)
+
+	creator := requireMinter(ownerCol)
+	colRoyalty := loadCollectionRoyalty(ownerCol)
+	reserveCollectionSupply(ownerCol, 1, uint64(args.editions))
 
 	// Create NFT
 	nftID := getNFTCount()
-	creatorPtr := sdk.GetEnvKey("msg.sender")
-	creator := *creatorPtr
+	mintNFT(nftID, ownerCol, creator, args, colRoyalty)
+	setNFTCount(nftID + 1)
+}
+
+// requireMinter returns msg.sender if it may mint into ownerCol, aborts otherwise.
+func requireMinter(ownerCol string) string {
+	creator := *sdk.GetEnvKey("msg.sender")
+	if !canMintInto(ownerCol, creator) {
+		sdk.Abort("only collection owner or minter can mint")
+	}
+	return creator
+}
+
+// mintNFT writes the state of one new NFT and emits its mint event.
+// Callers validate the arguments and advance the NFT counter.
+func mintNFT(nftID uint64, ownerCol, creator string, args mintArgs, colRoyalty *Royalty) {
+	royalty := args.royalty
+	if royalty == nil {
+		royalty = colRoyalty // fall back to collection default
+	}
 
-	saveNFTCore(nftID, name, desc, meta)
-	saveNFTCreator(nftID, creator, single)
+	saveNFTCore(nftID, args.name, args.desc, args.meta)
+	saveNFTCreator(nftID, creator, args.single, ownerCol)
 	saveNFTOwnerCollection(nftID, ownerCol)
-	if editions > 1 {
-		saveNFTEditionCount(nftID, editions)
+	owner := collectionOwner(ownerCol)
+	if args.editions > 1 {
+		saveNFTEditionCount(nftID, args.editions)
+		// all editions start with the collection owner
+		addEditionRangeToOwner(nftID, 0, args.editions, owner)
 	}
+	ownerHoldings(owner).add(nftID, uint64(args.editions))
+	collectionMembers(ownerCol).add(nftID, uint64(args.editions))
 
-	EmitMintEvent(nftID, creator, ownerCol, editions)
-	setNFTCount(nftID + 1)
-	return nil
+	if royalty != nil {
+		saveNFTRoyalty(nftID, *royalty)
+		EmitMintEvent(nftID, creator, ownerCol, args.editions, royalty.Bps, resolveRoyaltyRecipient(*royalty, ownerCol))
+	} else {
+		EmitMintEvent(nftID, creator, ownerCol, args.editions, 0, "")
+	}
 }
 
 // ==================================
@@ -80,6 +224,7 @@ func Mint(payload *string) *string {
 //
 //go:wasmexport nft_transfer
 func Transfer(payload *string) *string {
+	requireNotPaused(pauseTransfer)
 	if payload == nil || *payload == "" {
 		sdk.Abort("empty payload")
 	}
@@ -97,81 +242,271 @@ func Transfer(payload *string) *string {
 	}
 	target := parts[2]
 
-	// Load edition count and base ownership
-	nftEdTotal := loadNFTEditionCount(id)
-	nftOwnerCol := loadNFTOwnerCollection(id)
+	transferToken(newTransferScope(target, sdk.GetEnvKey("msg.caller")), id, ed)
+	return nil
+}
+
+// TransferBatch moves several NFTs and/or editions into one target collection.
+// Payload format: "<owner>_<collection>|<nftID>|<editionIndex>|<nftID>|<editionIndex>|..."
+// - editionIndex may be empty for unique NFTs (defaults to 0).
+// - Every item runs the same checks as nft_transfer; any failing item reverts the whole batch.
+//
+//go:wasmexport nft_transferBatch
+func TransferBatch(payload *string) *string {
+	requireNotPaused(pauseTransfer)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	p := *payload
+	sep := indexByte(p, '|')
+	if sep <= 0 {
+		sdk.Abort("invalid payload")
+	}
+	target := p[:sep]
+	rest := p[sep+1:]
+
+	// Target collection, its owner and the caller's market entry are
+	// resolved once for the whole batch
+	scope := newTransferScope(target, sdk.GetEnvKey("msg.caller"))
+
+	items := 0
+	for len(rest) > 0 {
+		// each item is "<id>|<ed>"
+		i := indexByte(rest, '|')
+		if i < 0 {
+			sdk.Abort("invalid batch item")
+		}
+		idStr := rest[:i]
+		rest = rest[i+1:]
+		edStr := rest
+		if j := indexByte(rest, '|'); j >= 0 {
+			edStr, rest = rest[:j], rest[j+1:]
+		} else {
+			rest = ""
+		}
+		if items == maxTransferBatch {
+			sdk.Abort("batch too large")
+		}
+
+		id := parseUint64Field(idStr, 0, len(idStr))
+		var ed uint32
+		if len(edStr) > 0 {
+			ed = parseUint32Field(edStr, 0, len(edStr))
+		}
+		transferToken(scope, id, ed)
+		items++
+	}
+	if items == 0 {
+		sdk.Abort("no nfts to transfer")
+	}
+	return nil
+}
+
+// TransferEditions moves a contiguous range of editions of one multi-edition NFT.
+// Payload format: "<nftID>|<fromEdition>-<toEdition>|<owner>_<collection>"
+// - The range is inclusive and limited to maxEditionRange editions.
+// - All editions in the range must currently sit in the same collection and none may be burned.
+// - The owner index is written once and a single aggregated transfer event is emitted.
+//
+//go:wasmexport nft_transferEditions
+func TransferEditions(payload *string) *string {
+	requireNotPaused(pauseTransfer)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	rangeStr := parts[1]
+	target := parts[2]
+
+	dash := indexByte(rangeStr, '-')
+	if dash <= 0 || dash == len(rangeStr)-1 {
+		sdk.Abort("edition range must be <from>-<to>")
+	}
+	from := parseUint32Field(rangeStr, 0, dash)
+	to := parseUint32Field(rangeStr, dash+1, len(rangeStr))
+	if from > to {
+		sdk.Abort("invalid edition range")
+	}
+	if to-from >= maxEditionRange {
+		sdk.Abort("edition range too large")
+	}
 
-	// Determine effective edition ownership
-	var effectiveEd uint32
-	if *nftEdTotal > 1 {
-		effectiveEd = ed
-		if effectiveEd >= *nftEdTotal {
-			sdk.Abort("edition index out of range")
+	edTotal := *loadNFTEditionCount(id)
+	if edTotal <= 1 {
+		sdk.Abort("NFT has no editions")
+	}
+	if to >= edTotal {
+		sdk.Abort("edition index out of range")
+	}
+	loadCollection(target) // make sure the collection exists
+
+	// All editions must come from the same collection (resolves burned state too)
+	sourceCol, _, _ := loadTokenOwnerCollection(id, from)
+	if sourceCol == target {
+		sdk.Abort("source and target are the same")
+	}
+	baseOwnerCol := *loadNFTOwnerCollection(id)
+
+	currentOwner := collectionOwner(sourceCol)
+	targetOwner := collectionOwner(target)
+	collectionOnly := currentOwner == targetOwner
+
+	// Authorization: owner, market or operator cover the whole range;
+	// otherwise every single edition must be approved for the caller.
+	caller := sdk.GetEnvKey("msg.caller")
+	approvedOnly := false
+	if !isAuthorized(caller, &currentOwner, id, sourceCol, marketPermTransfer) && !isOperator(currentOwner, sourceCol, caller) {
+		if collectionOnly {
+			sdk.Abort("only owner/market can change collection")
 		}
-		// Resolve edition-specific owner if an override exists
-		resolved := resolveEditionOwnerAndCollection(id, *nftOwnerCol, effectiveEd)
-		nftOwnerCol = &resolved
-	} else {
-		effectiveEd = 0
+		approvedOnly = true
+	}
+	if !collectionOnly {
+		requireUnbound(id, currentOwner)
 	}
 
-	// Burn protection logic
-	if *nftEdTotal > 1 {
-		if eo := loadEditionOverride(id, effectiveEd); eo != nil && eo.Burned {
+	for ed := from; ; ed++ {
+		eo := loadEditionOverride(id, ed)
+		if eo != nil && eo.Burned {
 			sdk.Abort("edition is burned")
 		}
-	} else {
-		if eo := loadEditionOverride(id, 0); eo != nil && eo.Burned {
-			sdk.Abort("nft is burned")
+		holder := baseOwnerCol
+		if eo != nil {
+			holder = eo.OwnerCollection
+		}
+		if holder != sourceCol {
+			sdk.Abort("editions held in different collections")
+		}
+		requireNotInAuction(id, ed)
+		if approvedOnly && !isApproved(id, ed, caller) {
+			sdk.Abort("only market or owner can transfer")
+		}
+		clearApproval(id, ed)
+		clearListing(id, ed)
+		saveEditionOverride(id, ed, target)
+		if ed == to {
+			break
 		}
 	}
 
+	// Update owned index only when actual owner changes
+	if !collectionOnly {
+		moveEditionRange(id, from, to+1, currentOwner, targetOwner)
+		moveHolding(id, uint64(to-from)+1, currentOwner, targetOwner)
+	}
+	moveMembership(id, uint64(to-from)+1, sourceCol, target)
+	emitTransferRange(id, from, to, sourceCol, target)
+	return nil
+}
+
+// transferScope holds what every item of an nft_transfer or
+// nft_transferBatch call shares: the caller, its market entry (nil if the
+// caller is no market) and the target collection with its owner.
+type transferScope struct {
+	caller      *string
+	market      *Market
+	target      string
+	targetOwner string
+}
+
+// newTransferScope makes sure the target collection exists and resolves the
+// lookups shared by all items of a transfer.
+func newTransferScope(target string, caller *string) *transferScope {
+	loadCollection(target) // make sure the collection exists
+	scope := &transferScope{caller: caller, target: target, targetOwner: collectionOwner(target)}
+	if caller != nil {
+		scope.market = findMarket(*caller)
+	}
+	return scope
+}
+
+// isAuthorized is the package level isAuthorized for marketPermTransfer,
+// using the market entry resolved in newTransferScope.
+func (scope *transferScope) isAuthorized(owner string, nftID uint64, ownerCol string) bool {
+	if scope.caller == nil {
+		return false
+	}
+	if *scope.caller == owner {
+		return true
+	}
+	return marketAllows(scope.market, *scope.caller, ownerCol, marketPolicyCollection(nftID, ownerCol), marketPermTransfer)
+}
+
+// transferToken moves one NFT or edition into the scope's target after
+// checking authorization, soulbound and burned state. Shared by nft_transfer
+// and nft_transferBatch so both enforce identical rules.
+func transferToken(scope *transferScope, id uint64, ed uint32) {
+	// Resolve current ownership (aborts on out-of-range or burned editions)
+	ownerCol, effectiveEd, nftEdTotal := loadTokenOwnerCollection(id, ed)
+
 	// Prevent no-op transfer
-	if *nftOwnerCol == target {
+	if ownerCol == scope.target {
 		sdk.Abort("source and target are the same")
 	}
+	requireNotInAuction(id, effectiveEd)
 
-	// Identify current and target owners
-	currentOwner, _ := splitOwnerCollection(*nftOwnerCol)
-	targetOwner, _ := splitOwnerCollection(target)
-	collectionOnly := currentOwner == targetOwner
-
-	caller := sdk.GetEnvKey("msg.caller")
-	marketContracts := GetMarketContractsCSV(nil)
+	// Identify current owner; the target owner comes from the scope
+	currentOwner := collectionOwner(ownerCol)
+	collectionOnly := currentOwner == scope.targetOwner
 
 	// Authorization logic
 	if !collectionOnly {
-		if !isAuthorized(caller, &currentOwner, marketContracts) {
+		if !scope.isAuthorized(currentOwner, id, ownerCol) &&
+			!isOperator(currentOwner, ownerCol, scope.caller) &&
+			!isApproved(id, effectiveEd, scope.caller) {
 			sdk.Abort("only market or owner can transfer")
 		}
-		creator, single := loadNFTCreator(id)
-		if single && *creator != currentOwner {
-			sdk.Abort("nft bound to owner")
-		}
+		requireUnbound(id, currentOwner)
 	} else {
-		if !isAuthorized(caller, &currentOwner, marketContracts) {
+		if !scope.isAuthorized(currentOwner, id, ownerCol) && !isOperator(currentOwner, ownerCol, scope.caller) {
 			sdk.Abort("only owner/market can change collection")
 		}
 	}
 
-	// Perform state write
-	if *nftEdTotal > 1 {
-		loadCollection(target) // make sure the collection exists
+	moveTokenBetween(id, effectiveEd, nftEdTotal, ownerCol, currentOwner, scope.target, scope.targetOwner)
+}
+
+// moveToken writes the move of one NFT or edition from ownerCol into target:
+// clears approval and listing, updates ownership, indexes and emits the
+// transfer event. Callers must have done all authorization checks.
+func moveToken(id uint64, ed uint32, edTotal uint32, ownerCol string, target string) {
+	moveTokenBetween(id, ed, edTotal, ownerCol, collectionOwner(ownerCol), target, collectionOwner(target))
+}
+
+// moveTokenBetween is moveToken with the owners of both collections already
+// resolved.
+func moveTokenBetween(id uint64, ed uint32, edTotal uint32, ownerCol, currentOwner, target, targetOwner string) {
+	collectionOnly := currentOwner == targetOwner
+
+	clearApproval(id, ed)
+	clearListing(id, ed)
+	if edTotal > 1 {
 		// edition transfer
-		saveEditionOverride(id, effectiveEd, target)
-		emitTransfer(id, &effectiveEd, *nftOwnerCol, target)
+		saveEditionOverride(id, ed, target)
+		emitTransfer(id, &ed, ownerCol, target)
 		// Update owned index only when actual owner changes
 		if !collectionOnly {
-			addEditionToOwnerMapping(id, effectiveEd, targetOwner)
+			moveEditionRange(id, ed, ed+1, currentOwner, targetOwner)
 		}
 	} else {
-		loadCollection(target) // make sure the collection exists
 		// single nft transfer
 		saveNFTOwnerCollection(id, target)
-		emitTransfer(id, nil, *nftOwnerCol, target)
+		emitTransfer(id, nil, ownerCol, target)
+	}
+	if !collectionOnly {
+		moveHolding(id, 1, currentOwner, targetOwner)
 	}
+	moveMembership(id, 1, ownerCol, target)
+}
 
-	return nil
+// requireUnbound aborts if the NFT is soulbound (single transfer) and has
+// already left its creator, i.e. owner is not the creator.
+func requireUnbound(id uint64, owner string) {
+	creator, single := loadNFTCreator(id)
+	if single && *creator != owner {
+		sdk.Abort("nft bound to owner")
+	}
 }
 
 // ==================================
@@ -188,62 +523,40 @@ func Transfer(payload *string) *string {
 //
 //go:wasmexport nft_burn
 func Burn(nftId *string) *string {
+	requireNotPaused(pauseBurn)
 	if nftId == nil || *nftId == "" {
 		sdk.Abort("empty id")
 	}
-	p := *nftId
-	idx := indexByte(p, '|')
-
-	var nftID uint64
-	var edPtr *uint32
-
 	// Parse "<id>" or "<id>|<edition>"
-	if idx == -1 {
-		nftID = parseUint64Field(p, 0, len(p))
-	} else {
-		nftID = parseUint64Field(p, 0, idx)
-		if idx < len(p)-1 {
-			ed := parseUint32Field(p, idx+1, len(p))
-			edPtr = &ed
-		} else {
-			sdk.Abort("invalid edition format")
-		}
-	}
+	nftID, reqEd, hasEdition := parseNFTRef(*nftId)
 
-	// Load only owner+collection (fast lookup, no metadata)
-	ownerColPtr := loadNFTOwnerCollection(nftID)
-	ownerCol := *ownerColPtr
-	owner, collection := splitOwnerCollection(ownerCol)
+	// Resolve the current holder of the edition (aborts if out of range or already burned)
+	ownerCol, burnEd, edCount := loadTokenOwnerCollection(nftID, reqEd)
+	if hasEdition && edCount <= 1 {
+		sdk.Abort("NFT has no editions")
+	}
+	if !hasEdition && edCount > 1 {
+		sdk.Abort("edition required to burn multi-edition NFT")
+	}
+	requireNotInAuction(nftID, burnEd)
+	owner := collectionOwner(ownerCol)
 
-	// Authorization: only the owner can burn
+	// Authorization: only the owner, one of its operators or a market with burn permission can burn
 	caller := sdk.GetEnvKey("msg.caller")
-	if !isAuthorized(caller, &owner, nil) {
+	if !isAuthorized(caller, &owner, nftID, ownerCol, marketPermBurn) && !isOperator(owner, ownerCol, caller) {
 		sdk.Abort("only owner can burn")
 	}
 
-	// Check editions
-	edCountPtr := loadNFTEditionCount(nftID)
-	edCount := *edCountPtr
-
-	if edPtr != nil {
-		// Attempting to burn a specific edition
-		if edCount <= 1 {
-			sdk.Abort("NFT has no editions")
-		}
-		if *edPtr >= edCount {
-			sdk.Abort("edition index out of range")
-		}
-		markEditionBurned(nftID, *edPtr)
-	} else {
-		// Base-level burn request
-		if edCount > 1 {
-			sdk.Abort("edition required to burn multi-edition NFT")
-		}
-		// Single-edition NFT; edition index implicitly 0
-		markEditionBurned(nftID, 0)
+	markEditionBurned(nftID, burnEd)
+	if edCount > 1 {
+		removeEditionFromOwnerMapping(nftID, burnEd, owner)
 	}
+	ownerHoldings(owner).remove(nftID, 1)
+	collectionMembers(ownerCol).remove(nftID, 1)
+	clearApproval(nftID, burnEd) // burned editions can no longer be moved by anyone
+	clearListing(nftID, burnEd)
 
-	emitBurn(nftID, edPtr, owner, collection)
+	emitBurn(nftID, editionRef(burnEd, edCount), owner, ownerCol)
 	return nil
 }
 
@@ -251,7 +564,7 @@ func Burn(nftId *string) *string {
 // Internal State I/O for NFT Core
 // ===============================
 
-// saveNFTCore stores immutable metadata in compact pipe-delimited format.
+// saveNFTCore stores the core record (tx, name, desc, meta) in compact pipe-delimited format.
 func saveNFTCore(nftID uint64, name, desc, meta string) {
 	txID := sdk.GetEnvKey("tx.id")
 	b := make([]byte, 0, len(*txID)+1+len(name)+1+len(desc)+1+len(meta))
@@ -265,11 +578,12 @@ func saveNFTCore(nftID uint64, name, desc, meta string) {
 	sdk.StateSetObject(nftCoreKey(nftID), string(b))
 }
 
-// saveNFTCreator stores creator + singleTransfer flag with minimal allocations.
-// Format: "creator|1" for restricted transfer, "creator|0" otherwise.
-func saveNFTCreator(nftID uint64, creator string, singleTransfer bool) {
-	// Pre-size buffer: creator length + 2 bytes ("|" + flag)
-	b := make([]byte, 0, len(creator)+2)
+// saveNFTCreator stores creator + singleTransfer flag + origin collection with minimal allocations.
+// Format: "creator|1|origin" for restricted transfer, "creator|0|origin" otherwise.
+// The origin is the "<owner>_<collection>" the NFT was minted into.
+func saveNFTCreator(nftID uint64, creator string, singleTransfer bool, originCollection string) {
+	// Pre-size buffer: creator length + 3 bytes ("|" + flag + "|") + origin
+	b := make([]byte, 0, len(creator)+3+len(originCollection))
 	b = append(b, creator...)
 	b = append(b, '|')
 	if singleTransfer {
@@ -277,17 +591,39 @@ func saveNFTCreator(nftID uint64, creator string, singleTransfer bool) {
 	} else {
 		b = append(b, '0')
 	}
+	b = append(b, '|')
+	b = append(b, originCollection...)
 	sdk.StateSetObject(creatorKey(nftID), string(b))
 }
 
 // loadNFTCreator returns (creatorAddress, isSingleTransferRestricted).
 func loadNFTCreator(nftID uint64) (*string, bool) {
+	creator, flag, _ := loadNFTCreatorRecord(nftID)
+	return &creator, flag
+}
+
+// loadNFTOrigin returns the "<owner>_<collection>" the NFT was minted into.
+func loadNFTOrigin(nftID uint64) string {
+	_, _, origin := loadNFTCreatorRecord(nftID)
+	return origin
+}
+
+// loadNFTCreatorRecord returns (creatorAddress, isSingleTransferRestricted, originCollection).
+// Records written before origins were tracked return an empty origin.
+func loadNFTCreatorRecord(nftID uint64) (string, bool, string) {
 	ptr := sdk.StateGetObject(creatorKey(nftID))
 	if ptr == nil || *ptr == "" {
 		sdk.Abort("creator missing")
 	}
-	creator, flag := split2Str(*ptr)
-	return &creator, flag == "1"
+	creator, rest := split2Str(*ptr)
+	if rest == "" {
+		sdk.Abort("invalid creator state")
+	}
+	origin := ""
+	if len(rest) > 2 {
+		origin = rest[2:]
+	}
+	return creator, rest[0] == '1', origin
 }
 
 func saveNFTOwnerCollection(nftID uint64, ownerCollection string) {
@@ -343,10 +679,11 @@ func parseEditionOverride(s string) EditionOverride {
 	return EditionOverride{OwnerCollection: owner, Burned: f == "1"}
 }
 
+// saveEditionOverride records the new holder of an edition.
+// The owner index is maintained separately by the caller.
 func saveEditionOverride(nftID uint64, editionIndex uint32, ownerCollection string) {
 	val := editionOverrideToStr(EditionOverride{OwnerCollection: ownerCollection, Burned: false})
 	sdk.StateSetObject(editionOverrideKey(nftID, editionIndex), val)
-	addEditionToOwnerMapping(nftID, editionIndex, ownerCollection[:indexByte(ownerCollection, '_')])
 }
 
 func markEditionBurned(nftID uint64, editionIndex uint32) {
@@ -377,19 +714,185 @@ func loadEditionOverride(nftID uint64, editionIndex uint32) *EditionOverride {
 // Owner Edition Index Data Mapping
 // ================================
 
-func addEditionToOwnerMapping(nftID uint64, editionIndex uint32, owner string) {
+//
+// Every owner address holding editions of a multi-edition NFT has one index
+// entry: a sorted list of disjoint [start,end) edition ranges, 8 bytes each
+// (big-endian uint32 pairs). Ranges keep the index small even for NFTs with
+// millions of editions: minting seeds a single range for the collection owner,
+// transfers split/merge ranges and burns remove the edition.
+// Unique NFTs are not indexed; their owner is the base owner record.
+// Owners indexed before the range entries existed still have the legacy
+// append-only list under kOwnedIdx; it is read as a fallback and replaced by a
+// range entry on the next change.
+
+// removeEditionFromOwnerMapping removes one edition from the owner's index.
+func removeEditionFromOwnerMapping(nftID uint64, editionIndex uint32, owner string) {
+	removeEditionRangeFromOwner(nftID, editionIndex, editionIndex+1, owner)
+}
+
+// moveEditionRange moves editions [start,end) from one owner's index to another's.
+func moveEditionRange(nftID uint64, start, end uint32, from, to string) {
+	removeEditionRangeFromOwner(nftID, start, end, from)
+	addEditionRangeToOwner(nftID, start, end, to)
+}
+
+// addEditionRangeToOwner merges [start,end) into the owner's index with a single state write.
+func addEditionRangeToOwner(nftID uint64, start, end uint32, owner string) {
+	ranges, legacy := readOwnedRanges(nftID, owner)
+	saveOwnedRanges(nftID, owner, mergeRange(ranges, start, end), legacy)
+}
+
+// mergeRange returns ranges with [start,end) merged in, keeping it sorted and disjoint.
+func mergeRange(ranges []uint32, start, end uint32) []uint32 {
+	out := make([]uint32, 0, len(ranges)+2)
+	placed := false
+	for i := 0; i+1 < len(ranges); i += 2 {
+		s, e := ranges[i], ranges[i+1]
+		switch {
+		case e < start: // entirely before
+			out = append(out, s, e)
+		case s > end: // entirely after
+			if !placed {
+				out = append(out, start, end)
+				placed = true
+			}
+			out = append(out, s, e)
+		default: // overlapping or adjacent -> merge
+			if s < start {
+				start = s
+			}
+			if e > end {
+				end = e
+			}
+		}
+	}
+	if !placed {
+		out = append(out, start, end)
+	}
+	return out
+}
+
+// removeEditionRangeFromOwner cuts [start,end) out of the owner's index.
+func removeEditionRangeFromOwner(nftID uint64, start, end uint32, owner string) {
+	ranges, legacy := readOwnedRanges(nftID, owner)
+	if len(ranges) == 0 {
+		return
+	}
+	out := make([]uint32, 0, len(ranges)+2)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		s, e := ranges[i], ranges[i+1]
+		if e <= start || s >= end {
+			out = append(out, s, e)
+			continue
+		}
+		if s < start {
+			out = append(out, s, start)
+		}
+		if e > end {
+			out = append(out, end, e)
+		}
+	}
+	saveOwnedRanges(nftID, owner, out, legacy)
+}
+
+// loadOwnedRanges returns the owner's flat [start0,end0,start1,end1,...] list.
+func loadOwnedRanges(nftID uint64, owner string) []uint32 {
+	ranges, _ := readOwnedRanges(nftID, owner)
+	return ranges
+}
+
+// readOwnedRanges is loadOwnedRanges that also reports whether the list came
+// from the legacy edition list. That list was never pruned, so only the
+// editions the owner still holds are kept.
+func readOwnedRanges(nftID uint64, owner string) ([]uint32, bool) {
+	if ptr := sdk.StateGetObject(ownedIndexKey(nftID, owner)); ptr != nil && *ptr != "" {
+		return unpackUint32List(*ptr), false
+	}
+	ptr := sdk.StateGetObject(legacyOwnedIndexKey(nftID, owner))
+	if ptr == nil || *ptr == "" {
+		return nil, false
+	}
+	base := *loadNFTOwnerCollection(nftID)
+	var ranges []uint32
+	for _, ed := range unpackUint32List(*ptr) {
+		if eo := loadEditionOverride(nftID, ed); eo != nil && eo.Burned {
+			continue
+		}
+		if collectionOwner(resolveEditionOwnerAndCollection(nftID, base, ed)) == owner {
+			ranges = mergeRange(ranges, ed, ed+1)
+		}
+	}
+	return ranges, true
+}
+
+// saveOwnedRanges writes the flat range list, deleting the entry once it is
+// empty. A legacy edition list read by readOwnedRanges is migrated (dropped).
+func saveOwnedRanges(nftID uint64, owner string, ranges []uint32, legacy bool) {
+	if legacy {
+		sdk.StateDeleteObject(legacyOwnedIndexKey(nftID, owner))
+	}
 	key := ownedIndexKey(nftID, owner)
-	ptr := sdk.StateGetObject(key)
-	buf := []byte{}
-	if ptr != nil && *ptr != "" {
-		buf = []byte(*ptr)
+	if len(ranges) == 0 {
+		sdk.StateDeleteObject(key)
+		return
+	}
+	buf := make([]byte, 4*len(ranges))
+	for i, v := range ranges {
+		binary.BigEndian.PutUint32(buf[i*4:], v)
 	}
-	var tmp [4]byte
-	binary.BigEndian.PutUint32(tmp[:], editionIndex)
-	buf = append(buf, tmp[:]...)
 	sdk.StateSetObject(key, string(buf))
 }
 
+// unpackUint32List decodes consecutive big-endian uint32 values.
+func unpackUint32List(buf string) []uint32 {
+	list := make([]uint32, 0, len(buf)/4)
+	for i := 0; i+3 < len(buf); i += 4 {
+		list = append(list, binary.BigEndian.Uint32([]byte(buf[i:i+4])))
+	}
+	return list
+}
+
+// moveHolding moves n tokens of an NFT between two owners' holdings.
+func moveHolding(nftID uint64, n uint64, from, to string) {
+	ownerHoldings(from).remove(nftID, n)
+	ownerHoldings(to).add(nftID, n)
+}
+
+// moveMembership moves n tokens of an NFT between two collections' member sets.
+func moveMembership(nftID uint64, n uint64, from, to string) {
+	collectionMembers(from).remove(nftID, n)
+	collectionMembers(to).add(nftID, n)
+}
+
+// loadTokenOwnerCollection resolves the current "<owner>_<collection>" of an NFT
+// or one of its editions. The edition index is ignored for unique NFTs.
+// Aborts if the edition is out of range or already burned.
+// Returns (ownerCollection, effectiveEdition, editionTotal).
+func loadTokenOwnerCollection(nftID uint64, ed uint32) (string, uint32, uint32) {
+	edTotal := *loadNFTEditionCount(nftID)
+	ownerCol := *loadNFTOwnerCollection(nftID)
+
+	var effectiveEd uint32
+	if edTotal > 1 {
+		if ed >= edTotal {
+			sdk.Abort("edition index out of range")
+		}
+		effectiveEd = ed
+	}
+	if eo := loadEditionOverride(nftID, effectiveEd); eo != nil {
+		if eo.Burned {
+			if edTotal > 1 {
+				sdk.Abort("edition is burned")
+			}
+			sdk.Abort("nft is burned")
+		}
+		if edTotal > 1 {
+			ownerCol = eo.OwnerCollection
+		}
+	}
+	return ownerCol, effectiveEd, edTotal
+}
+
 func resolveEditionOwnerAndCollection(nftID uint64, base string, editionIndex uint32) string {
 	if eo := loadEditionOverride(nftID, editionIndex); eo != nil {
 		return eo.OwnerCollection
@@ -441,11 +944,12 @@ func validateMintArgs(name, desc string) {
 // Ownership Validation
 // ====================
 
-// isAuthorized returns true if caller == owner OR caller == one of the markets.
-// If both validations fail, returns false.
+// isAuthorized returns true if caller == owner OR caller is an enabled market
+// holding perm for tokens in ownerCol that the market policy of the NFT's
+// origin collection allows. If both validations fail, returns false.
 //
 // It’s intentionally small because this is hit in hot code paths like transfer/burn.
-func isAuthorized(caller, owner, marketContracts *string) bool {
+func isAuthorized(caller, owner *string, nftID uint64, ownerCol string, perm uint64) bool {
 	if caller == nil {
 		return false
 	}
@@ -455,9 +959,5 @@ func isAuthorized(caller, owner, marketContracts *string) bool {
 		return true
 	}
 	// Market matches
-	if marketContracts != nil && containsInCSV(*marketContracts, *caller) {
-		return true
-
-	}
-	return false
+	return isMarketFor(c, ownerCol, marketPolicyCollection(nftID, ownerCol), perm)
 }
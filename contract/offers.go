@@ -0,0 +1,454 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// =======
+// OFFERS
+// =======
+//
+// Anyone can make an offer on an NFT or edition that is not necessarily
+// listed, or a collection offer for a number of items of any NFT minted into
+// a collection ("any hive:alice_0 for 50 HBD each"). The offered amount is
+// escrowed in the contract (sdk.HiveDraw) until the offer is accepted,
+// cancelled or refunded after it expired. Accepting (or filling one item of a
+// collection offer) pays the per-item amount out like a sale (see payoutSale)
+// and moves the token to the bidder's collection.
+//
+// Offers get sequential IDs ("offer_count") and are stored under
+// offerKey(offerID) as
+// "<nftID>|<edition>|<bidder>|<amount>|<asset>|<expiryHeight>|<bidderCollection>"
+// or, for collection offers (empty token fields),
+// "||<bidder>|<amount>|<asset>|<expiryHeight>|<bidderCollection>|<owner>_<collection>|<remaining>".
+// The open offers of a token or collection are enumerable through idSets
+// (see indexes.go) holding offer IDs instead of NFT IDs.
+
+type Offer struct {
+	NftID     uint64
+	Edition   uint32
+	Bidder    string
+	Amount    uint64
+	Asset     sdk.Asset
+	Expiry    uint64 // block height from which the offer can no longer be accepted
+	BidderCol string // "<owner>_<collection>" the token is delivered to
+
+	Collection string // collection offers: "<owner>_<collection>" the NFTs must be minted into
+	Remaining  uint64 // collection offers: items still wanted (escrow = Amount * Remaining)
+}
+
+// MakeOffer escrows an offer for an NFT or edition; the amount is drawn from
+// the caller via intents.
+// Payload format: "<nftID>|<editionIndex>|<amount>|<asset>|<expiryBlockHeight>|<owner>_<collection>"
+// - editionIndex may be empty for unique NFTs (defaults to 0)
+// - asset is "hive" or "hbd"
+// - expiryBlockHeight must be in the future and at most maxOfferBlocks ahead
+// - the collection receives the token on acceptance and must belong to the caller
+// An offer event carrying the new offer ID is emitted.
+//
+//go:wasmexport offer_make
+func MakeOffer(payload *string) *string {
+	requireNotPaused(pauseTrade)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 6)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	var ed uint32
+	if edStr := parts[1]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	amount := parsePrice(parts[2])
+	asset := parseSaleAsset(parts[3])
+	expiry := mustParseUint64(parts[4])
+	target := parts[5]
+	height := currentBlockHeight()
+	if expiry <= height {
+		sdk.Abort("expiry must be in the future")
+	}
+	if expiry-height > maxOfferBlocks {
+		sdk.Abort("offer too long")
+	}
+
+	ownerCol, effectiveEd, edTotal := loadTokenOwnerCollection(id, ed)
+	bidder := *sdk.GetEnvKey("msg.caller")
+	if bidder == collectionOwner(ownerCol) {
+		sdk.Abort("cannot make offer on own nft")
+	}
+	loadCollection(target) // make sure the collection exists
+	if collectionOwner(target) != bidder {
+		sdk.Abort("target collection not owned by bidder")
+	}
+
+	sdk.HiveDraw(int64(amount), asset)
+	o := Offer{NftID: id, Edition: effectiveEd, Bidder: bidder, Amount: amount, Asset: asset, Expiry: expiry, BidderCol: target}
+	offerID := getOfferCount()
+	saveOffer(offerID, o)
+	tokenOffers(id, effectiveEd).add(offerID, 1)
+	setOfferCount(offerID + 1)
+	emitOffer("offer", offerID, editionRef(effectiveEd, edTotal), o)
+	return nil
+}
+
+// MakeCollectionOffer escrows an offer for several items of a collection; the
+// total (amount * quantity) is drawn from the caller via intents.
+// Payload format: "<owner>_<collection>|<amountPerItem>|<asset>|<quantity>|<expiryBlockHeight>|<bidderOwner>_<bidderCollection>"
+// - any NFT or edition minted into the collection can fill one item (see offer_fill)
+// - quantity is at most maxOfferItems
+// - asset, expiry and bidder collection follow the rules of offer_make
+// An offer event carrying the new offer ID is emitted.
+//
+//go:wasmexport offer_makeCollection
+func MakeCollectionOffer(payload *string) *string {
+	requireNotPaused(pauseTrade)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 6)
+	ownerCol := parts[0]
+	amount := parsePrice(parts[1])
+	asset := parseSaleAsset(parts[2])
+	quantity := mustParseUint64(parts[3])
+	expiry := mustParseUint64(parts[4])
+	target := parts[5]
+	if quantity == 0 || quantity > maxOfferItems {
+		sdk.Abort("invalid quantity")
+	}
+	if amount > (1<<63-1)/quantity {
+		sdk.Abort("invalid price")
+	}
+	height := currentBlockHeight()
+	if expiry <= height {
+		sdk.Abort("expiry must be in the future")
+	}
+	if expiry-height > maxOfferBlocks {
+		sdk.Abort("offer too long")
+	}
+
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	bidder := *sdk.GetEnvKey("msg.caller")
+	loadCollection(target) // make sure the collection exists
+	if collectionOwner(target) != bidder {
+		sdk.Abort("target collection not owned by bidder")
+	}
+
+	sdk.HiveDraw(int64(amount*quantity), asset)
+	o := Offer{Bidder: bidder, Amount: amount, Asset: asset, Expiry: expiry, BidderCol: target, Collection: ownerCol, Remaining: quantity}
+	offerID := getOfferCount()
+	saveOffer(offerID, o)
+	collectionOffers(ownerCol).add(offerID, 1)
+	setOfferCount(offerID + 1)
+	emitOffer("offer", offerID, nil, o)
+	return nil
+}
+
+// CancelOffer withdraws an offer and refunds the escrow (of the items not
+// filled yet) to the bidder.
+// Payload: "<offerID>"
+// Only the bidder may cancel an open offer; once expired anyone may trigger
+// the refund. Cancelling keeps working while trading is paused.
+// An offerCancel event is emitted.
+//
+//go:wasmexport offer_cancel
+func CancelOffer(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	offerID := mustParseUint64(*payload)
+	o := loadOffer(offerID)
+	if o == nil {
+		sdk.Abort("offer not found")
+	}
+	caller := sdk.GetEnvKey("msg.caller")
+	if (caller == nil || *caller != o.Bidder) && currentBlockHeight() < o.Expiry {
+		sdk.Abort("only bidder can cancel offer")
+	}
+
+	removeOffer(offerID, *o)
+	if o.Collection != "" {
+		sdk.HiveTransfer(sdk.Address(o.Bidder), int64(o.Amount*o.Remaining), o.Asset)
+		emitOffer("offerCancel", offerID, nil, *o)
+		return nil
+	}
+	sdk.HiveTransfer(sdk.Address(o.Bidder), int64(o.Amount), o.Asset)
+	emitOffer("offerCancel", offerID, editionRef(o.Edition, *loadNFTEditionCount(o.NftID)), *o)
+	return nil
+}
+
+// AcceptOffer sells the token to the bidder of an offer.
+// Payload: "<offerID>"
+// The caller must be the current owner or a market allowed to transfer the
+// token. Royalties and the owner are paid from the escrow and the token moves
+// to the bidder's collection (offerAccept, transfer and sale events).
+//
+//go:wasmexport offer_accept
+func AcceptOffer(payload *string) *string {
+	requireNotPaused(pauseTrade | pauseTransfer)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	offerID := mustParseUint64(*payload)
+	o := loadOffer(offerID)
+	if o == nil {
+		sdk.Abort("offer not found")
+	}
+	if o.Collection != "" {
+		sdk.Abort("use offer_fill for collection offers")
+	}
+
+	ownerCol, effectiveEd, edTotal := requireOfferSeller(*o, o.NftID, o.Edition)
+	removeOffer(offerID, *o)
+	edRef := editionRef(effectiveEd, edTotal)
+	emitOffer("offerAccept", offerID, edRef, *o)
+	sellToBidder(*o, o.NftID, effectiveEd, edTotal, ownerCol)
+	return nil
+}
+
+// FillOffer sells one NFT or edition into a collection offer.
+// Payload format: "<offerID>|<nftID>|<editionIndex>"
+// - editionIndex may be empty for unique NFTs (defaults to 0)
+// - the NFT must have been minted into the offer's collection
+// The caller must be the current owner or a market allowed to transfer the
+// token. One item's amount is paid out and the token moves to the bidder's
+// collection (offerFill, transfer and sale events); the offer closes once
+// all items are filled.
+//
+//go:wasmexport offer_fill
+func FillOffer(payload *string) *string {
+	requireNotPaused(pauseTrade | pauseTransfer)
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 3)
+	offerID := mustParseUint64(parts[0])
+	id := parseUint64Field(parts[1], 0, len(parts[1]))
+	var ed uint32
+	if edStr := parts[2]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	o := loadOffer(offerID)
+	if o == nil {
+		sdk.Abort("offer not found")
+	}
+	if o.Collection == "" {
+		sdk.Abort("not a collection offer")
+	}
+	if loadNFTOrigin(id) != o.Collection {
+		sdk.Abort("nft not from offer collection")
+	}
+
+	ownerCol, effectiveEd, edTotal := requireOfferSeller(*o, id, ed)
+	o.Remaining--
+	if o.Remaining == 0 {
+		removeOffer(offerID, *o)
+	} else {
+		saveOffer(offerID, *o)
+	}
+	emitOfferFill(offerID, id, editionRef(effectiveEd, edTotal), *o)
+	sellToBidder(*o, id, effectiveEd, edTotal, ownerCol)
+	return nil
+}
+
+// GetOffersFor lists the open offers of an NFT or edition, one page at a time.
+//
+// Payload: "<nftID>|<editionIndex>|<cursor>|<limit>"
+// - editionIndex may be empty for unique NFTs (defaults to 0)
+// - cursor is the number of offers already read (empty = 0)
+// - limit defaults to and is capped at maxPageLimit
+//
+// Returns comma-separated "<offerID>|<bidder>|<amount>|<asset>|<expiryHeight>"
+// records. Expired offers are included until they are cancelled. Order
+// follows the on-chain index and may change when offers are removed.
+//
+//go:wasmexport offers_for
+func GetOffersFor(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 4)
+	id := parseUint64Field(parts[0], 0, len(parts[0]))
+	var ed uint32
+	if edStr := parts[1]; len(edStr) > 0 {
+		ed = parseUint32Field(edStr, 0, len(edStr))
+	}
+	cursor, limit := parseCursorLimit(parts[2], parts[3])
+	if *loadNFTEditionCount(id) <= 1 {
+		ed = 0
+	}
+	set := tokenOffers(id, ed)
+	members, _ := set.head()
+
+	b := make([]byte, 0, limit*48)
+	for slot := cursor; slot < members && slot < cursor+limit; slot++ {
+		if slot > cursor {
+			b = append(b, ',')
+		}
+		offerID := set.member(slot)
+		o := loadOffer(offerID)
+		b = strconv.AppendUint(b, offerID, 10)
+		b = append(b, '|')
+		b = append(b, o.Bidder...)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, o.Amount, 10)
+		b = append(b, '|')
+		b = append(b, o.Asset...)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, o.Expiry, 10)
+	}
+	s := string(b)
+	return &s
+}
+
+// GetCollectionOffers lists the open collection offers of a collection, one page at a time.
+//
+// Payload: "<owner>_<collection>|<cursor>|<limit>" (paging as for offers_for)
+//
+// Returns comma-separated "<offerID>|<bidder>|<amountPerItem>|<asset>|<expiryHeight>|<remaining>"
+// records. Expired offers are included until they are cancelled.
+//
+//go:wasmexport col_offers
+func GetCollectionOffers(payload *string) *string {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	ownerCol, cursor, limit := parsePage(*payload)
+	loadCollection(ownerCol) // ensures "<owner>_<collection>" exists
+	set := collectionOffers(ownerCol)
+	members, _ := set.head()
+
+	b := make([]byte, 0, limit*56)
+	for slot := cursor; slot < members && slot < cursor+limit; slot++ {
+		if slot > cursor {
+			b = append(b, ',')
+		}
+		offerID := set.member(slot)
+		o := loadOffer(offerID)
+		b = strconv.AppendUint(b, offerID, 10)
+		b = append(b, '|')
+		b = append(b, o.Bidder...)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, o.Amount, 10)
+		b = append(b, '|')
+		b = append(b, o.Asset...)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, o.Expiry, 10)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, o.Remaining, 10)
+	}
+	s := string(b)
+	return &s
+}
+
+// ================
+// Offer Settlement
+// ================
+
+// requireOfferSeller checks that the caller may sell the token into the offer
+// and returns its owner collection, effective edition and edition total.
+func requireOfferSeller(o Offer, id uint64, ed uint32) (string, uint32, uint32) {
+	if currentBlockHeight() >= o.Expiry {
+		sdk.Abort("offer expired")
+	}
+	ownerCol, effectiveEd, edTotal := loadTokenOwnerCollection(id, ed)
+	requireNotInAuction(id, effectiveEd)
+	seller := collectionOwner(ownerCol)
+	caller := sdk.GetEnvKey("msg.caller")
+	if !isAuthorized(caller, &seller, id, ownerCol, marketPermTransfer) {
+		sdk.Abort("only market or owner can accept offer")
+	}
+	if seller == o.Bidder {
+		sdk.Abort("bidder already owns nft")
+	}
+	if collectionOwner(o.BidderCol) != o.Bidder {
+		sdk.Abort("target collection not owned by bidder")
+	}
+	requireUnbound(id, seller)
+	return ownerCol, effectiveEd, edTotal
+}
+
+// sellToBidder pays one item's amount from the escrow and moves the token.
+func sellToBidder(o Offer, id uint64, ed uint32, edTotal uint32, ownerCol string) {
+	seller := collectionOwner(ownerCol)
+	royalty := payoutSale(id, o.Amount, o.Asset, seller)
+	moveToken(id, ed, edTotal, ownerCol, o.BidderCol)
+	emitSale(id, editionRef(ed, edTotal), seller, o.Bidder, o.Amount, o.Asset, royalty)
+}
+
+// ==============
+// Offer Storage
+// ==============
+
+// tokenOffers is the set of open offer IDs on one edition.
+func tokenOffers(nftID uint64, editionIndex uint32) idSet {
+	var scope [12]byte
+	packU64LEInline(nftID, scope[:])
+	packU32LEInline(editionIndex, scope[8:])
+	return idSet{headTag: kOfferHead, slotTag: kOfferSlot, posTag: kOfferPos, scope: string(scope[:])}
+}
+
+// collectionOffers is the set of open collection offer IDs on a collection.
+func collectionOffers(ownerCol string) idSet {
+	return idSet{headTag: kColOffHead, slotTag: kColOffSlot, posTag: kColOffPos, scope: ownerCol}
+}
+
+func loadOffer(offerID uint64) *Offer {
+	ptr := sdk.StateGetObject(offerKey(offerID))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	parts := splitPipeLayout(*ptr, 7, 9)
+	o := &Offer{
+		Bidder:    parts[2],
+		Amount:    mustParseUint64(parts[3]),
+		Asset:     sdk.Asset(parts[4]),
+		Expiry:    mustParseUint64(parts[5]),
+		BidderCol: parts[6],
+	}
+	if len(parts) == 9 {
+		o.Collection = parts[7]
+		o.Remaining = mustParseUint64(parts[8])
+		return o
+	}
+	o.NftID = mustParseUint64(parts[0])
+	o.Edition = parseUint32Field(parts[1], 0, len(parts[1]))
+	return o
+}
+
+func saveOffer(offerID uint64, o Offer) {
+	b := make([]byte, 0, len(o.Bidder)+len(o.BidderCol)+len(o.Collection)+100)
+	if o.Collection == "" {
+		b = strconv.AppendUint(b, o.NftID, 10)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, uint64(o.Edition), 10)
+	} else {
+		b = append(b, '|')
+	}
+	b = append(b, '|')
+	b = append(b, o.Bidder...)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, o.Amount, 10)
+	b = append(b, '|')
+	b = append(b, o.Asset...)
+	b = append(b, '|')
+	b = strconv.AppendUint(b, o.Expiry, 10)
+	b = append(b, '|')
+	b = append(b, o.BidderCol...)
+	if o.Collection != "" {
+		b = append(b, '|')
+		b = append(b, o.Collection...)
+		b = append(b, '|')
+		b = strconv.AppendUint(b, o.Remaining, 10)
+	}
+	sdk.StateSetObject(offerKey(offerID), string(b))
+}
+
+// removeOffer deletes the offer and drops it from its token or collection index.
+func removeOffer(offerID uint64, o Offer) {
+	sdk.StateDeleteObject(offerKey(offerID))
+	if o.Collection != "" {
+		collectionOffers(o.Collection).remove(offerID, 1)
+		return
+	}
+	tokenOffers(o.NftID, o.Edition).remove(offerID, 1)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// ================
+// EMERGENCY PAUSE
+// ================
+//
+// PAUSER holders (see roles.go) can halt state-changing exports while an
+// incident is investigated, either globally or per operation class.
+// Getters, admin/role/market management, revocations (nft_revoke,
+// nft_setOperator with "false"), list_cancel and offer_cancel always keep
+// working.
+//
+// The paused classes are stored as a decimal bitmask under "paused".
+
+const pausedKey = "paused"
+
+const (
+	pauseMint       uint64 = 1 << iota // nft_mint, nft_mintBatch, drop_mint
+	pauseTransfer                      // nft_transfer, nft_transferBatch, nft_transferEditions
+	pauseBurn                          // nft_burn
+	pauseApprove                       // nft_approve, nft_setOperator (granting)
+	pauseMetadata                      // nft_setMeta, nft_setMetaUpdater, nft_freezeMeta
+	pauseCollection                    // col_create, col_update, col_transfer, col_setMaxSupply, col_setMarkets, col_setDrop, col_addMinter, col_removeMinter
+	pauseTrade                         // list_create, list_buy, auction_start, auction_bid, auction_settle, drop_mint, offer_make, offer_makeCollection, offer_accept, offer_fill
+
+	pauseAll = pauseMint | pauseTransfer | pauseBurn | pauseApprove | pauseMetadata | pauseCollection | pauseTrade
+)
+
+// Pause halts the given operation classes (adds to already paused ones).
+// Payload: comma-separated class names ("mint,transfer"); empty or "all" pauses everything.
+// Classes: mint, transfer, burn, approve, metadata, collection, trade
+// Only PAUSER holders may call this. A pause event is emitted.
+//
+//go:wasmexport admin_pause
+func Pause(payload *string) *string {
+	classes := parsePauseClasses(payload)
+	caller := requireRole(rolePauser)
+
+	mask := loadPausedMask() | classes
+	savePausedMask(mask)
+	emitPause(mask, caller)
+	return nil
+}
+
+// Unpause resumes the given operation classes.
+// Payload: same format as admin_pause; empty or "all" resumes everything.
+// Only PAUSER holders may call this. A pause event is emitted.
+//
+//go:wasmexport admin_unpause
+func Unpause(payload *string) *string {
+	classes := parsePauseClasses(payload)
+	caller := requireRole(rolePauser)
+
+	mask := loadPausedMask() &^ classes
+	savePausedMask(mask)
+	emitPause(mask, caller)
+	return nil
+}
+
+// IsPaused reports whether operations are paused.
+//
+// Payload: "<class>" or empty (empty → "true" if any class is paused)
+//
+// Returns: "true" or "false"
+//
+//go:wasmexport is_paused
+func IsPaused(payload *string) *string {
+	mask := loadPausedMask()
+	paused := mask != 0
+	if payload != nil && *payload != "" {
+		paused = mask&parsePauseClasses(payload) != 0
+	}
+	out := "false"
+	if paused {
+		out = "true"
+	}
+	return &out
+}
+
+// ================
+// Internal Helpers
+// ================
+
+// requireNotPaused aborts if the operation class is paused.
+func requireNotPaused(class uint64) {
+	if loadPausedMask()&class != 0 {
+		sdk.Abort("contract is paused")
+	}
+}
+
+func loadPausedMask() uint64 {
+	ptr := sdk.StateGetObject(pausedKey)
+	if ptr == nil || *ptr == "" {
+		return 0
+	}
+	return mustParseUint64(*ptr)
+}
+
+func savePausedMask(mask uint64) {
+	if mask == 0 {
+		sdk.StateDeleteObject(pausedKey)
+		return
+	}
+	sdk.StateSetObject(pausedKey, strconv.FormatUint(mask, 10))
+}
+
+func parsePauseClasses(payload *string) uint64 {
+	if payload == nil || *payload == "" || *payload == "all" {
+		return pauseAll
+	}
+	p := *payload
+	var mask uint64
+	start := 0
+	for i := 0; i <= len(p); i++ {
+		if i < len(p) && p[i] != ',' {
+			continue
+		}
+		mask |= pauseClass(p[start:i])
+		start = i + 1
+	}
+	return mask
+}
+
+func pauseClass(name string) uint64 {
+	switch name {
+	case "mint":
+		return pauseMint
+	case "transfer":
+		return pauseTransfer
+	case "burn":
+		return pauseBurn
+	case "approve":
+		return pauseApprove
+	case "metadata":
+		return pauseMetadata
+	case "collection":
+		return pauseCollection
+	case "trade":
+		return pauseTrade
+	}
+	sdk.Abort("unknown pause class")
+	return 0
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"vsc_nft_mgmt/sdk"
+)
+
+// ==============
+// ROLE REGISTRY
+// ==============
+//
+// Administrative powers are split into roles so that e.g. ops can manage
+// markets without holding the admin key:
+//
+//	ADMIN           grant/revoke roles; implicitly holds every other role
+//	MARKET_MANAGER  add/remove market contracts
+//	PAUSER          pause/unpause mutations
+//	METADATA_ORACLE update metadata of any NFT whose metadata is not frozen
+//
+// The admin from admin.go always holds ADMIN and cannot be revoked; the
+// admin handover itself stays reserved to that address.
+//
+// Grants live under "rl_<role>|<account>" with value "1".
+
+const (
+	roleAdmin          = "ADMIN"
+	roleMarketManager  = "MARKET_MANAGER"
+	rolePauser         = "PAUSER"
+	roleMetadataOracle = "METADATA_ORACLE"
+)
+
+// GrantRole grants a role to an account.
+// Payload: "<role>|<account>"
+// Only ADMIN holders may call this. A role event is emitted.
+//
+//go:wasmexport role_grant
+func GrantRole(payload *string) *string {
+	role, account := parseRolePayload(payload)
+	requireRole(roleAdmin)
+	if !isValidAccount(account) {
+		sdk.Abort("invalid account address")
+	}
+	if hasRole(role, account) {
+		return nil // already granted (explicitly or via ADMIN)
+	}
+
+	sdk.StateSetObject(roleKey(role, account), "1")
+	emitRole(role, account, true)
+	return nil
+}
+
+// RevokeRole removes a role from an account.
+// Payload: "<role>|<account>"
+// Only ADMIN holders may call this; the admin itself cannot be revoked.
+// A role event is emitted.
+//
+//go:wasmexport role_revoke
+func RevokeRole(payload *string) *string {
+	role, account := parseRolePayload(payload)
+	requireRole(roleAdmin)
+	if account == loadAdmin() {
+		sdk.Abort("cannot revoke admin")
+	}
+	key := roleKey(role, account)
+	if ptr := sdk.StateGetObject(key); ptr == nil || *ptr == "" {
+		sdk.Abort("role not granted")
+	}
+
+	sdk.StateDeleteObject(key)
+	emitRole(role, account, false)
+	return nil
+}
+
+// HasRole returns "true" if the account holds the role (explicitly or via ADMIN).
+//
+// Payload: "<role>|<account>"
+//
+//go:wasmexport role_has
+func HasRole(payload *string) *string {
+	role, account := parseRolePayload(payload)
+	out := "false"
+	if hasRole(role, account) {
+		out = "true"
+	}
+	return &out
+}
+
+// ================
+// Internal Helpers
+// ================
+
+// requireRole aborts unless msg.sender holds the role. Returns the caller.
+func requireRole(role string) string {
+	sender := sdk.GetEnvKey("msg.sender")
+	if sender == nil || !hasRole(role, *sender) {
+		sdk.Abort("missing role " + role)
+	}
+	return *sender
+}
+
+func hasRole(role string, account string) bool {
+	if account == "" {
+		return false
+	}
+	if account == loadAdmin() {
+		return true
+	}
+	if ptr := sdk.StateGetObject(roleKey(role, account)); ptr != nil && *ptr == "1" {
+		return true
+	}
+	if role == roleAdmin {
+		return false
+	}
+	ptr := sdk.StateGetObject(roleKey(roleAdmin, account))
+	return ptr != nil && *ptr == "1"
+}
+
+func parseRolePayload(payload *string) (string, string) {
+	if payload == nil || *payload == "" {
+		sdk.Abort("empty payload")
+	}
+	parts := splitFixedPipe(*payload, 2)
+	if !isKnownRole(parts[0]) {
+		sdk.Abort("unknown role")
+	}
+	if parts[1] == "" {
+		sdk.Abort("account required")
+	}
+	return parts[0], parts[1]
+}
+
+func isKnownRole(role string) bool {
+	switch role {
+	case roleAdmin, roleMarketManager, rolePauser, roleMetadataOracle:
+		return true
+	}
+	return false
+}
+
+func roleKey(role string, account string) string {
+	return "rl_" + role + "|" + account
+}
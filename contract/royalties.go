@@ -0,0 +1,192 @@
+package main
+
+import (
+	"strconv"
+	"vsc_nft_mgmt/sdk"
+)
+
+// =================
+// CREATOR ROYALTIES
+// =================
+//
+// Royalties are fixed per NFT at mint time and stored as "<bps>|<recipient>"
+// under royaltyKey(nftID). A collection may define a default royalty under
+// "cr_<owner>_<collection>" which is copied to every NFT minted into it without
+// an explicit royalty. An empty recipient resolves to the owner of the
+// collection the NFT was minted into.
+//
+// The recipient can also be a split list "<address>:<bps>,<address>:<bps>"
+// (max maxRoyaltySplits entries) whose shares must add up to the royalty bps.
+//
+// Native sales (listings.go) pay royalties out automatically. External markets
+// query nft_royaltyInfo (EIP-2981 style) and pay out on resale.
+
+const bpsDenominator = 10000 // 100% in basis points
+
+type Royalty struct {
+	Bps       uint32
+	Recipient string // single address, split list or empty (collection owner)
+}
+
+type RoyaltyShare struct {
+	Recipient string
+	Bps       uint32
+}
+
+// parseRoyaltyArgs validates the optional "<bps>|<recipient>" payload fields.
+// Returns nil if no royalty is requested.
+func parseRoyaltyArgs(bpsStr, recipient string) *Royalty {
+	if bpsStr == "" || bpsStr == "0" {
+		if recipient != "" {
+			sdk.Abort("royalty bps required")
+		}
+		return nil
+	}
+	bps := parseUint32Field(bpsStr, 0, len(bpsStr))
+	if bps > maxRoyaltyBps {
+		sdk.Abort("royalty too high")
+	}
+	if shares := parseRoyaltySplit(recipient); shares != nil {
+		var sum uint32
+		for i, sh := range shares {
+			for k := 0; k < i; k++ {
+				if shares[k].Recipient == sh.Recipient {
+					sdk.Abort("duplicate royalty recipient")
+				}
+			}
+			sum += sh.Bps
+		}
+		if sum != bps {
+			sdk.Abort("royalty shares must add up to royalty bps")
+		}
+	} else if recipient != "" && !isValidAccount(recipient) {
+		sdk.Abort("invalid royalty recipient")
+	}
+	return &Royalty{Bps: bps, Recipient: recipient}
+}
+
+// parseRoyaltySplit parses "<address>:<bps>,<address>:<bps>" into shares.
+// Returns nil if s is a single plain address (or empty).
+func parseRoyaltySplit(s string) []RoyaltyShare {
+	if s == "" {
+		return nil
+	}
+	n := 1
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' {
+			n++
+		}
+	}
+	if n == 1 && !hasBpsSuffix(s) {
+		return nil // plain address
+	}
+	if n > maxRoyaltySplits {
+		sdk.Abort("too many royalty recipients")
+	}
+
+	shares := make([]RoyaltyShare, 0, n)
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] != ',' {
+			continue
+		}
+		entry := s[start:i]
+		if !hasBpsSuffix(entry) {
+			sdk.Abort("royalty share requires <address>:<bps>")
+		}
+		colon := lastIndexByte(entry, ':')
+		addr := entry[:colon]
+		if !isValidAccount(addr) {
+			sdk.Abort("invalid royalty recipient")
+		}
+		bps := parseUint32Field(entry, colon+1, len(entry))
+		if bps == 0 {
+			sdk.Abort("royalty share must be positive")
+		}
+		shares = append(shares, RoyaltyShare{Recipient: addr, Bps: bps})
+		start = i + 1
+	}
+	return shares
+}
+
+// hasBpsSuffix reports whether s ends in ":<digits>" (a royalty share entry).
+func hasBpsSuffix(s string) bool {
+	colon := lastIndexByte(s, ':')
+	if colon <= 0 || colon == len(s)-1 {
+		return false
+	}
+	for i := colon + 1; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// royaltyShares resolves the recipients of a royalty into individual shares.
+func royaltyShares(r Royalty, originCollection string) []RoyaltyShare {
+	if shares := parseRoyaltySplit(r.Recipient); shares != nil {
+		return shares
+	}
+	return []RoyaltyShare{{Recipient: resolveRoyaltyRecipient(r, originCollection), Bps: r.Bps}}
+}
+
+func royaltyToStr(r Royalty) string {
+	b := make([]byte, 0, len(r.Recipient)+11)
+	b = strconv.AppendUint(b, uint64(r.Bps), 10)
+	b = append(b, '|')
+	b = append(b, r.Recipient...)
+	return string(b)
+}
+
+func parseRoyalty(s string) Royalty {
+	bps, recipient := split2Str(s)
+	return Royalty{Bps: parseUint32Field(bps, 0, len(bps)), Recipient: recipient}
+}
+
+func saveNFTRoyalty(nftID uint64, r Royalty) {
+	sdk.StateSetObject(royaltyKey(nftID), royaltyToStr(r))
+}
+
+func loadNFTRoyalty(nftID uint64) *Royalty {
+	ptr := sdk.StateGetObject(royaltyKey(nftID))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	r := parseRoyalty(*ptr)
+	return &r
+}
+
+func saveCollectionRoyalty(ownerCollection string, r Royalty) {
+	sdk.StateSetObject(colRoyaltyKey(ownerCollection), royaltyToStr(r))
+}
+
+func loadCollectionRoyalty(ownerCollection string) *Royalty {
+	ptr := sdk.StateGetObject(colRoyaltyKey(ownerCollection))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	r := parseRoyalty(*ptr)
+	return &r
+}
+
+// colRoyaltyKey returns "cr_<owner>_<collection>" holding the default royalty.
+func colRoyaltyKey(ownerCollection string) string {
+	return "cr_" + ownerCollection
+}
+
+// resolveRoyaltyRecipient returns the explicit recipient or, if empty,
+// the current owner of the collection the NFT was minted into.
+func resolveRoyaltyRecipient(r Royalty, originCollection string) string {
+	if r.Recipient != "" || originCollection == "" {
+		return r.Recipient
+	}
+	return collectionOwner(originCollection)
+}
+
+// royaltyAmount computes floor(price * bps / 10000) without overflowing uint64.
+//
+//go:inline
+func royaltyAmount(price uint64, bps uint32) uint64 {
+	return price/bpsDenominator*uint64(bps) + price%bpsDenominator*uint64(bps)/bpsDenominator
+}
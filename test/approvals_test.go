@@ -0,0 +1,88 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+// // approval tests
+func TestApprovalTransfer(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	// mint 10 nft editions
+	CallContract(t, ct, "nft_mint",
+		[]byte("hive:someone_0|name|description|false|10|test=123"),
+		nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// approve by non-owner (should fail)
+	CallContract(t, ct, "nft_approve", []byte("0|3|hive:escrow"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	// transfer by escrow before approval (should fail)
+	CallContract(t, ct, "nft_transfer", []byte("0|3|hive:someoneelse_0"), nil, "hive:escrow", false, uint(100_000_000), "")
+
+	// approved address must be a valid account
+	CallContract(t, ct, "nft_approve", []byte("0|3|escrow"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_approve", []byte("0|3|hive:esc\"row"), nil, "hive:someone", false, uint(100_000_000), "")
+
+	// approve escrow for edition 3
+	CallContract(t, ct, "nft_approve", []byte("0|3|hive:escrow"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_getApproved", []byte("0|3"), nil, "hive:someone", true, uint(100_000_000), "hive:escrow")
+
+	// approval is per edition
+	CallContract(t, ct, "nft_transfer", []byte("0|4|hive:someoneelse_0"), nil, "hive:escrow", false, uint(100_000_000), "")
+
+	// transfer edition 3 by escrow (should succeed and clear the approval)
+	CallContract(t, ct, "nft_transfer", []byte("0|3|hive:someoneelse_0"), nil, "hive:escrow", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0|3"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_transfer", []byte("0|3|hive:someone_0"), nil, "hive:escrow", false, uint(100_000_000), "")
+}
+
+func TestApprovalRevokeAndBurn(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	// mint unique nft
+	CallContract(t, ct, "nft_mint",
+		[]byte("hive:someone_0|name|description|false||test=123"),
+		nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	CallContract(t, ct, "nft_approve", []byte("0||hive:escrow"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_revoke", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "")
+	// nothing left to revoke
+	CallContract(t, ct, "nft_revoke", []byte("0"), nil, "hive:someone", false, uint(100_000_000), "")
+
+	// burn clears the approval
+	CallContract(t, ct, "nft_approve", []byte("0||hive:escrow"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_burn", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_getApproved", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_approve", []byte("0||hive:escrow"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
+// // operator tests
+func TestOperatorApprovals(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionC|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|10|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_1|name|description|false||test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// operator limited to a collection of somebody else (should fail)
+	CallContract(t, ct, "nft_setOperator", []byte("hive:game|true|hive:someoneelse_0"), nil, "hive:someone", false, uint(100_000_000), "")
+
+	// operator limited to collection 0
+	CallContract(t, ct, "nft_setOperator", []byte("game|true|hive:someone_0"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_setOperator", []byte("hive:game|true|hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_isOperator", []byte("hive:someone_0|hive:game"), nil, "hive:someone", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_isOperator", []byte("hive:someone|hive:game"), nil, "hive:someone", true, uint(100_000_000), "false")
+	CallContract(t, ct, "nft_transfer", []byte("0|1|hive:someoneelse_0"), nil, "hive:game", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_burn", []byte("0|2"), nil, "hive:game", true, uint(1_000_000_000), "")
+	// nft in collection 1 is not covered
+	CallContract(t, ct, "nft_transfer", []byte("1||hive:someoneelse_0"), nil, "hive:game", false, uint(100_000_000), "")
+
+	// owner-wide operator
+	CallContract(t, ct, "nft_setOperator", []byte("hive:game|true|"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("1||hive:someoneelse_0"), nil, "hive:game", true, uint(1_000_000_000), "")
+
+	// revoke
+	CallContract(t, ct, "nft_setOperator", []byte("hive:game|false|"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_isOperator", []byte("hive:someone|hive:game"), nil, "hive:someone", true, uint(100_000_000), "false")
+}
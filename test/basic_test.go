@@ -18,6 +18,101 @@ func TestAdminMarket(t *testing.T) {
 
 }
 
+func TestMarketRegistry(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|desc|false||meta"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_1|name|desc|false||meta"), nil, "hive:someone", true, uint(100_000_000), "")
+
+	CallContract(t, ct, "add_market", []byte("vscxyz|true|transfer,burn|hive:someone_0|Main Market"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscabc|false|||"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscbad|true|mint||"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscbad|true"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscbad|true|||Main | Market"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscbad,vscxyz"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "market_get", []byte("vscxyz"), nil, "hive:someone", true, uint(100_000_000), "true|transfer,burn|hive:someone_0|Main Market")
+	CallContract(t, ct, "market_list", []byte("1|1"), nil, "hive:someone", true, uint(100_000_000), "vscabc")
+	CallContract(t, ct, "get_markets", []byte(""), nil, "hive:someone", true, uint(100_000_000), "vscxyz|vscabc")
+
+	// scoped to collection 0 only; the disabled market has no powers at all
+	CallContract(t, ct, "nft_transfer", []byte("1||hive:someoneelse_0"), nil, "vscxyz", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someoneelse_0"), nil, "vscabc", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someone_1"), nil, "vscxyz", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_burn", []byte("0"), nil, "vscxyz", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_burn", []byte("0"), nil, "vscxyz", true, uint(100_000_000), "")
+}
+
+func TestCollectionMarkets(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|desc|false||meta"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscxyz"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscabc"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_markets", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "vscxyz,vscabc")
+
+	CallContract(t, ct, "col_setMarkets", []byte("hive:someone_0|deny|vscxyz"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setMarkets", []byte("hive:someone_0|deny|vscxyz|vscabc"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setMarkets", []byte("hive:someone_0|deny|vsc\"xyz"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setMarkets", []byte("hive:someone_0|deny|vscxyz"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_markets", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "vscabc")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someoneelse_0"), nil, "vscxyz", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setMarkets", []byte("hive:someone_0|allow|vscxyz"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someoneelse_0"), nil, "vscabc", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someoneelse_0"), nil, "vscxyz", true, uint(100_000_000), "")
+	// the policy of the origin collection still applies at the new owner
+	CallContract(t, ct, "col_markets", []byte("hive:someoneelse_0"), nil, "hive:someone", true, uint(100_000_000), "vscxyz,vscabc")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someone_0"), nil, "vscabc", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someone_0"), nil, "vscxyz", true, uint(100_000_000), "")
+}
+
+func TestRoles(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "add_market", []byte("vscxyz"), nil, "hive:ops", false, uint(100_000_000), "")
+	CallContract(t, ct, "role_grant", []byte("MARKET_MANAGER|hive:ops"), nil, "hive:ops", false, uint(100_000_000), "")
+	CallContract(t, ct, "role_grant", []byte("MARKET_MANAGER|hive:o,ps"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "role_grant", []byte("MARKET_MANAGER|hive:ops"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "role_has", []byte("MARKET_MANAGER|hive:ops"), nil, "hive:someone", true, uint(100_000_000), "true")
+	CallContract(t, ct, "role_has", []byte("PAUSER|hive:ops"), nil, "hive:someone", true, uint(100_000_000), "false")
+	CallContract(t, ct, "add_market", []byte("vscxyz"), nil, "hive:ops", true, uint(100_000_000), "")
+	CallContract(t, ct, "role_revoke", []byte("MARKET_MANAGER|hive:ops"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "remove_market", []byte("vscxyz"), nil, "hive:ops", false, uint(100_000_000), "")
+	CallContract(t, ct, "role_revoke", []byte("ADMIN|hive:contractowner"), nil, "hive:contractowner", false, uint(100_000_000), "")
+}
+
+func TestPause(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "admin_pause", []byte("mint"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "admin_pause", []byte("mint"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "is_paused", []byte("mint"), nil, "hive:someone", true, uint(100_000_000), "true")
+	CallContract(t, ct, "is_paused", []byte("transfer"), nil, "hive:someone", true, uint(100_000_000), "false")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|desc|false||meta"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_get", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "admin_unpause", []byte(""), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "is_paused", []byte(""), nil, "hive:someone", true, uint(100_000_000), "false")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|desc|false||meta"), nil, "hive:someone", true, uint(100_000_000), "")
+}
+
+func TestAdminTransfer(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "admin_get", []byte(""), nil, "hive:someone", true, uint(100_000_000), "hive:contractowner|")
+	CallContract(t, ct, "admin_transfer", []byte("hive:newadmin"), nil, "hive:someone", false, uint(100_000_000), "")
+	// invalid addresses (should fail)
+	CallContract(t, ct, "admin_transfer", []byte("newadmin"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "admin_transfer", []byte("hive:new|admin"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "admin_transfer", []byte("hive:newadmin"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "admin_get", []byte(""), nil, "hive:someone", true, uint(100_000_000), "hive:contractowner|hive:newadmin")
+	CallContract(t, ct, "admin_accept", []byte(""), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "admin_accept", []byte(""), nil, "hive:newadmin", true, uint(100_000_000), "")
+	CallContract(t, ct, "admin_get", []byte(""), nil, "hive:someone", true, uint(100_000_000), "hive:newadmin|")
+	CallContract(t, ct, "add_market", []byte("vscxyz"), nil, "hive:contractowner", false, uint(100_000_000), "")
+	CallContract(t, ct, "add_market", []byte("vscxyz"), nil, "hive:newadmin", true, uint(100_000_000), "")
+}
+
 // // collection tests
 
 func TestColCreateSuccess(t *testing.T) {
@@ -51,6 +146,48 @@ func TestColCreateFails(t *testing.T) {
 
 }
 
+func TestColUpdate(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// not the owner (should fail)
+	CallContract(t, ct, "col_update", []byte("hive:someone_0|new description|img=new"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_update", []byte("hive:someone_0|new description|img=new"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_get", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "hive:someone|0|col_create-tx|collectionA|new description|img=new")
+
+	// update and lock for good
+	// metadata starting with "true|" does not lock
+	CallContract(t, ct, "col_update", []byte("hive:someone_0|final description|true|img=final"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_isLocked", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "false")
+	CallContract(t, ct, "col_lock", []byte("hive:someone_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_lock", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_isLocked", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "true")
+	CallContract(t, ct, "col_update", []byte("hive:someone_0|again|img=again"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
+func TestColMaxSupply(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_setMaxSupply", []byte("hive:someone_0|2|"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_supply", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "0|2|0|0")
+
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=1"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	// raising the cap (should fail)
+	CallContract(t, ct, "col_setMaxSupply", []byte("hive:someone_0|3|"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setMaxSupply", []byte("hive:someone_0|1|"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_supply", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "1|1|1|0")
+	// cap reached (should fail)
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=2"), nil, "hive:someone", false, uint(100_000_000), "")
+
+	// caps given at creation; metadata with '|' needs the long layout
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|10|50|img=a|b"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_supply", []byte("hive:someone_1"), nil, "hive:someone", true, uint(100_000_000), "0|10|0|50")
+	CallContract(t, ct, "col_create", []byte("collectionC|my description||5|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_supply", []byte("hive:someone_2"), nil, "hive:someone", true, uint(100_000_000), "0|0|0|5")
+	CallContract(t, ct, "col_create", []byte("collectionD|my description|0||img=testurl"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionD|my description|img=a|b"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
 // // nft tests
 func TestMintUniqueNFTSingleSuccess(t *testing.T) {
 	ct := SetupContractTest()
@@ -87,7 +224,8 @@ func TestMintUniqueNFTSuccess(t *testing.T) {
 		[]byte("hive:someone_0|name|description|true|1|test=123,test2=abc"),
 		nil, "hive:someone", true, uint(1_000_000_000), "")
 
-	// mint nft to another collection
+	// mint nft to another collection (only after being granted as minter)
+	CallContract(t, ct, "col_addMinter", []byte("hive:someoneelse_0|hive:someone"), nil, "hive:someoneelse", true, uint(100_000_000), "")
 	CallContract(t, ct, "nft_mint",
 		[]byte("hive:someoneelse_0|name|description|false|1|test=123,test2=abc"),
 		nil, "hive:someone", true, uint(1_000_000_000), "")
@@ -166,6 +304,59 @@ func TestMintUniqueNFTFails(t *testing.T) {
 		[]byte("|asd|description|true|0|test=123"),
 		nil, "hive:someone", false, uint(100_000_000), "")
 
+	// // mint nft into a collection of another user
+	CallContract(t, ct, "nft_mint",
+		[]byte("hive:someone_0|asd|description|true|0|test=123"),
+		nil, "hive:someoneelse", false, uint(100_000_000), "")
+
+}
+
+// batch mint
+func TestMintBatch(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// invalid record aborts the whole batch
+	CallContract(t, ct, "nft_mintBatch", []byte("hive:someone_0\nname1|description|false||||test=1\n|description|false||||test=2"), nil, "hive:someone", false, uint(100_000_000), "")
+	// not the collection owner
+	CallContract(t, ct, "nft_mintBatch", []byte("hive:someone_0\nname1|description|false||||test=1"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+
+	CallContract(t, ct, "nft_mintBatch",
+		[]byte("hive:someone_0\nname1|description|false||||test=1\nname2|description|true|5|||test=2\nname3|description|false||250|hive:artist|test=3"),
+		nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// contiguous ids after the single mint
+	CallContract(t, ct, "nft_meta", []byte("1"), nil, "hive:someone", true, uint(100_000_000), "test=1")
+	CallContract(t, ct, "nft_supply", []byte("2"), nil, "hive:someone", true, uint(100_000_000), "5")
+	CallContract(t, ct, "nft_royaltyInfo", []byte("3|1000"), nil, "hive:someone", true, uint(100_000_000), "hive:artist|25")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=4"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_meta", []byte("4"), nil, "hive:someone", true, uint(100_000_000), "test=4")
+}
+
+// minters
+func TestCollectionMinters(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// grant minter by non-owner (should fail)
+	CallContract(t, ct, "col_addMinter", []byte("hive:someone_0|hive:mintbot"), nil, "hive:mintbot", false, uint(100_000_000), "")
+	// grant minter by owner
+	CallContract(t, ct, "col_addMinter", []byte("hive:someone_0|mintbot"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_addMinter", []byte("hive:someone_0|hive:mint|bot"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_addMinter", []byte("hive:someone_0|hive:mintbot"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_minters", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "hive:mintbot")
+	CallContract(t, ct, "nft_mint",
+		[]byte("hive:someone_0|name|description|false||test=123"),
+		nil, "hive:mintbot", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_creator", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "hive:mintbot")
+
+	// revoke minter
+	CallContract(t, ct, "col_removeMinter", []byte("hive:someone_0|hive:mintbot"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_mint",
+		[]byte("hive:someone_0|name|description|false||test=123"),
+		nil, "hive:mintbot", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_removeMinter", []byte("hive:someone_0|hive:mintbot"), nil, "hive:someone", false, uint(100_000_000), "")
 }
 
 // burn
@@ -413,3 +604,179 @@ func TestEditionTransfersBurnProtection(t *testing.T) {
 	CallContract(t, ct, "nft_burn", PayloadToJSON("0|8"), nil, "hive:someoneelse", false, uint(1_000_000_000), "")
 
 }
+
+// batch transfers
+func TestTransferBatch(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mintBatch",
+		[]byte("hive:someone_0\nname1|description|false||||test=1\nname2|description|false|10|||test=2"),
+		nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_burn", PayloadToJSON("1|7"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// not the owner (should fail)
+	CallContract(t, ct, "nft_transferBatch", []byte("hive:someoneelse_0|0||1|3"), nil, "hive:someoneelse", false, uint(1_000_000_000), "")
+	// one burned edition reverts the whole batch
+	CallContract(t, ct, "nft_transferBatch", []byte("hive:someoneelse_0|0||1|3|1|7"), nil, "hive:someone", false, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "true")
+
+	CallContract(t, ct, "nft_transferBatch", []byte("hive:someoneelse_0|0||1|3|1|4"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_ownerColOf", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "hive:someoneelse_0")
+	CallContract(t, ct, "nft_ownerColOf", []byte("1|3"), nil, "hive:someone", true, uint(100_000_000), "hive:someoneelse_0")
+	CallContract(t, ct, "nft_ownerColOf", []byte("1|4"), nil, "hive:someone", true, uint(100_000_000), "hive:someoneelse_0")
+	CallContract(t, ct, "nft_ownerColOf", []byte("1|5"), nil, "hive:someone", true, uint(100_000_000), "hive:someone_0")
+
+	// a market moves a batch only where the origin collection's policy allows it
+	CallContract(t, ct, "add_market", []byte("vscxyz"), nil, "hive:contractowner", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_setMarkets", []byte("hive:someone_0|deny|vscxyz"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transferBatch", []byte("hive:someone_0|0||1|3"), nil, "vscxyz", false, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_setMarkets", []byte("hive:someone_0|allow|vscxyz"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transferBatch", []byte("hive:someone_0|0||1|3"), nil, "vscxyz", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_ownerColOf", []byte("1|3"), nil, "hive:someone", true, uint(100_000_000), "hive:someone_0")
+}
+
+// edition range transfers
+func TestTransferEditionRange(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|1000|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_burn", PayloadToJSON("0|150"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// not the owner (should fail)
+	CallContract(t, ct, "nft_transferEditions", []byte("0|0-99|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "")
+	// out of range / burned edition inside the range (should fail)
+	CallContract(t, ct, "nft_transferEditions", []byte("0|990-1000|hive:someoneelse_0"), nil, "hive:someone", false, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_transferEditions", []byte("0|100-199|hive:someoneelse_0"), nil, "hive:someone", false, uint(1_000_000_000), "")
+
+	CallContract(t, ct, "nft_transferEditions", []byte("0|0-99|hive:someoneelse_0"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0|0"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_isOwner", []byte("0|99"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_isOwner", []byte("0|100"), nil, "hive:someone", true, uint(100_000_000), "true")
+	// mixed source collections (should fail)
+	CallContract(t, ct, "nft_transferEditions", []byte("0|90-109|hive:someoneelse_0"), nil, "hive:someone", false, uint(1_000_000_000), "")
+}
+
+// owned edition index
+func TestOwnedEditionIndex(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|10|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// seeded at mint
+	CallContract(t, ct, "nft_ownedEditionRanges", []byte("0,hive:someone"), nil, "hive:someone", true, uint(100_000_000), "0-9")
+
+	// removed on transfer out, added on receive
+	CallContract(t, ct, "nft_transfer", []byte("0|3|hive:someoneelse_0"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_ownedEditionRanges", []byte("0,hive:someone"), nil, "hive:someone", true, uint(100_000_000), "0-2,4-9")
+	CallContract(t, ct, "nft_ownedEditionRanges", []byte("0,hive:someoneelse"), nil, "hive:someone", true, uint(100_000_000), "3")
+
+	// removed on burn
+	CallContract(t, ct, "nft_burn", PayloadToJSON("0|9"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_ownedEditionRanges", []byte("0,hive:someone"), nil, "hive:someone", true, uint(100_000_000), "0-2,4-8")
+
+	// moving back merges the ranges again
+	CallContract(t, ct, "nft_transfer", []byte("0|3|hive:someone_0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_ownedEditionRanges", []byte("0,hive:someone"), nil, "hive:someone", true, uint(100_000_000), "0-8")
+	// plain edition list
+	CallContract(t, ct, "nft_hasNFTEdition", []byte("0,hive:someone"), nil, "hive:someone", true, uint(100_000_000), "0,1,2,3,4,5,6,7,8")
+	CallContract(t, ct, "nft_hasNFTEdition", []byte("0,hive:someoneelse"), nil, "hive:someone", true, uint(100_000_000), "")
+}
+
+// per-owner balance and enumeration
+func TestOwnerTokens(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mintBatch",
+		[]byte("hive:someone_0\nname1|description|false||||test=1\nname2|description|false|3|||test=2"),
+		nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	CallContract(t, ct, "nft_balanceOf", []byte("hive:someone"), nil, "hive:someone", true, uint(100_000_000), "4")
+	CallContract(t, ct, "nft_tokensOf", []byte("hive:someone||"), nil, "hive:someone", true, uint(100_000_000), "|0|0,1|0,1|1,1|2")
+	// a full page leads with the cursor of the next page
+	CallContract(t, ct, "nft_tokensOf", []byte("hive:someone||2"), nil, "hive:someone", true, uint(100_000_000), "1.1.1|0|0,1|0")
+	CallContract(t, ct, "nft_tokensOf", []byte("hive:someone|1.1.1|2"), nil, "hive:someone", true, uint(100_000_000), "|1|1,1|2")
+
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someoneelse_0"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_burn", PayloadToJSON("1|2"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	// the cursor token is gone: the page ends instead of aborting
+	CallContract(t, ct, "nft_tokensOf", []byte("hive:someone|1.1.2|2"), nil, "hive:someone", true, uint(100_000_000), "|")
+	CallContract(t, ct, "nft_balanceOf", []byte("hive:someone"), nil, "hive:someone", true, uint(100_000_000), "2")
+	CallContract(t, ct, "nft_balanceOf", []byte("hive:someoneelse"), nil, "hive:someone", true, uint(100_000_000), "1")
+	CallContract(t, ct, "nft_tokensOf", []byte("hive:someoneelse||"), nil, "hive:someone", true, uint(100_000_000), "|0|0")
+}
+
+// editions "0" mint a unique NFT
+func TestMintZeroEditions(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_setMaxSupply", []byte("hive:someone_0|1|"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|0|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	CallContract(t, ct, "nft_balanceOf", []byte("hive:someone"), nil, "hive:someone", true, uint(100_000_000), "1")
+	CallContract(t, ct, "nft_tokensOf", []byte("hive:someone||"), nil, "hive:someone", true, uint(100_000_000), "|0|0")
+	CallContract(t, ct, "col_supply", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "1|1|1|0")
+	// the cap counted the NFT (should fail)
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|0|test=123"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
+// per-collection membership
+func TestCollectionNFTs(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mintBatch",
+		[]byte("hive:someone_0\nname1|description|false||||test=1\nname2|description|false|3|||test=2\nname3|description|false||||test=3"),
+		nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	CallContract(t, ct, "col_size", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "3")
+	CallContract(t, ct, "col_nfts", []byte("hive:someone_0||"), nil, "hive:someone", true, uint(100_000_000), "0,1,2")
+	CallContract(t, ct, "col_nfts", []byte("hive:someone_0|1|1"), nil, "hive:someone", true, uint(100_000_000), "1")
+
+	// collection-only move
+	CallContract(t, ct, "nft_transfer", []byte("0||hive:someone_1"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_size", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "2")
+	CallContract(t, ct, "col_nfts", []byte("hive:someone_1||"), nil, "hive:someone", true, uint(100_000_000), "0")
+
+	// an NFT stays listed while one of its editions is left
+	CallContract(t, ct, "nft_transferEditions", []byte("1|0-1|hive:someone_1"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_size", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "2")
+	CallContract(t, ct, "col_size", []byte("hive:someone_1"), nil, "hive:someone", true, uint(100_000_000), "2")
+
+	// unknown collection (should fail)
+	CallContract(t, ct, "col_size", []byte("hive:someone_5"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
+// collection ownership transfer
+func TestColTransfer(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_0|500|"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|3|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_transferEditions", []byte("1|2-2|hive:someone_1"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// not the owner (should fail)
+	CallContract(t, ct, "col_transfer", []byte("hive:someone_0|hive:studio"), nil, "hive:studio", false, uint(100_000_000), "")
+	// new owner must be a valid account (should fail)
+	CallContract(t, ct, "col_transfer", []byte("hive:someone_0|studio"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_transfer", []byte("hive:someone_0|hive:stu\"dio"), nil, "hive:someone", false, uint(100_000_000), "")
+	// NFTs in the collection move with it
+	CallContract(t, ct, "col_transfer", []byte("hive:someone_0|hive:studio"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_get", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "hive:studio|0|")
+	CallContract(t, ct, "nft_balanceOf", []byte("hive:studio"), nil, "hive:someone", true, uint(100_000_000), "3")
+	CallContract(t, ct, "nft_balanceOf", []byte("hive:someone"), nil, "hive:someone", true, uint(100_000_000), "1")
+	CallContract(t, ct, "nft_isOwner", []byte("0"), nil, "hive:studio", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_hasNFTEdition", []byte("1,hive:studio"), nil, "hive:someone", true, uint(100_000_000), "0,1")
+	CallContract(t, ct, "nft_hasNFTEdition", []byte("1,hive:someone"), nil, "hive:someone", true, uint(100_000_000), "2")
+
+	// minting rights and default royalties moved to the new owner
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=123"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=123"), nil, "hive:studio", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_royaltyInfo", []byte("2|1000"), nil, "hive:someone", true, uint(100_000_000), "hive:studio|50")
+	CallContract(t, ct, "nft_balanceOf", []byte("hive:studio"), nil, "hive:someone", true, uint(100_000_000), "4")
+}
@@ -9,6 +9,7 @@ import (
 
 	"vsc-node/lib/test_utils"
 	"vsc-node/modules/db/vsc/contracts"
+	ledgerDb "vsc-node/modules/db/vsc/ledger"
 	stateEngine "vsc-node/modules/state-processing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,6 +51,23 @@ func CallContract(
 	maxGas uint,
 	expectedOutput string,
 
+) (stateEngine.TxResult, uint, map[string][]string) {
+	return CallContractAt(t, ct, 0, action, payload, intents, authUser, expectedResult, maxGas, expectedOutput)
+}
+
+// CallContractAt is CallContract at a given block height (auctions, drops, offer expiry)
+func CallContractAt(
+	t *testing.T,
+	ct *test_utils.ContractTest,
+	blockHeight uint64,
+	action string,
+	payload json.RawMessage,
+	intents []contracts.Intent,
+	authUser string,
+	expectedResult bool,
+	maxGas uint,
+	expectedOutput string,
+
 ) (stateEngine.TxResult, uint, map[string][]string) {
 	fmt.Println(action)
 	fmt.Println(string(payload))
@@ -59,6 +77,7 @@ func CallContract(
 		Self: stateEngine.TxSelf{
 			TxId:                 fmt.Sprintf("%s-tx", action),
 			BlockId:              "block1",
+			BlockHeight:          blockHeight,
 			Index:                0,
 			OpIndex:              0,
 			Timestamp:            "2025-09-03T00:00:00",
@@ -162,6 +181,26 @@ func RunContractTests(t *testing.T, ct *test_utils.ContractTest, tests []Contrac
 	}
 }
 
+// CallGetterEmpty calls a getter and asserts that it returns an empty string
+// (CallContract skips the output check when the expected output is empty).
+func CallGetterEmpty(t *testing.T, ct *test_utils.ContractTest, action string, payload json.RawMessage) {
+	result, _, _ := CallContract(t, ct, action, payload, nil, "hive:someone", true, uint(100_000_000), "")
+	assert.Equal(t, "", result.Ret, action+" not empty")
+}
+
+// TransferIntent allows the contract to draw up to limit (e.g. "1.000") of token ("hive" or "hbd").
+func TransferIntent(limit string, token string) []contracts.Intent {
+	return []contracts.Intent{{
+		Type: "transfer.allow",
+		Args: map[string]string{"limit": limit, "token": token},
+	}}
+}
+
+// AssertBalance checks the ledger balance (in 0.001 units) of an account.
+func AssertBalance(t *testing.T, ct *test_utils.ContractTest, account string, asset ledgerDb.Asset, expected int64) {
+	assert.Equal(t, expected, ct.GetBalance(account, asset), fmt.Sprintf("balance of %s", account))
+}
+
 func toStringPtr(s string) *string {
 	return &s
 }
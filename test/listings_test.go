@@ -0,0 +1,261 @@
+package contract_test
+
+import (
+	"testing"
+
+	ledgerDb "vsc-node/modules/db/vsc/ledger"
+)
+
+// // listing tests
+func TestListings(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false|5|500|hive:artist|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// only the owner can list, for a positive hive/hbd price
+	CallContract(t, ct, "list_create", []byte("0|1|1000|hive"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "list_create", []byte("0|1|0|hive"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "list_create", []byte("0|1|1000|btc"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "list_create", []byte("0|1|1000|hive"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "list_get", []byte("0|1"), nil, "hive:someone", true, uint(100_000_000), "hive:someone|1000|hive")
+
+	// buying needs an intent covering the price
+	CallContract(t, ct, "list_buy", []byte("0|1|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "list_buy", []byte("0|1|hive:someone_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+
+	// transfers drop the listing
+	CallContract(t, ct, "nft_transfer", []byte("0|1|hive:someoneelse_0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "list_get", []byte("0|1"), nil, "hive:someone", true, uint(100_000_000), "")
+
+	CallContract(t, ct, "list_create", []byte("0|2|2000|hbd"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "list_cancel", []byte("0|2"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "list_cancel", []byte("0|2"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "list_get", []byte("0|2"), nil, "hive:someone", true, uint(100_000_000), "")
+}
+
+func TestListingsBuy(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false|5|500|hive:artist|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	ct.Deposit("hive:someoneelse", 5_000, ledgerDb.AssetHive)
+
+	CallContract(t, ct, "list_create", []byte("0|1|1000|hive"), nil, "hive:someone", true, uint(100_000_000), "")
+	// an intent below the price is not enough
+	CallContract(t, ct, "list_buy", []byte("0|1|hive:someoneelse_0"), TransferIntent("0.999", "hive"), "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "list_buy", []byte("0|1|hive:someoneelse_0"), TransferIntent("1.000", "hive"), "hive:someoneelse", true, uint(1_000_000_000), "")
+
+	// the buyer owns the edition, the listing is gone and 5% went to the artist
+	CallContract(t, ct, "nft_isOwner", []byte("0|1"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_isOwner", []byte("0|1"), nil, "hive:someone", true, uint(100_000_000), "false")
+	CallGetterEmpty(t, ct, "list_get", []byte("0|1"))
+	CallContract(t, ct, "list_buy", []byte("0|1|hive:someoneelse_0"), TransferIntent("1.000", "hive"), "hive:someoneelse", false, uint(100_000_000), "")
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHive, 4_000)
+	AssertBalance(t, ct, "hive:artist", ledgerDb.AssetHive, 50)
+	AssertBalance(t, ct, "hive:someone", ledgerDb.AssetHive, 950)
+}
+
+// // auction tests
+func TestAuctions(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|5|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	CallContract(t, ct, "auction_start", []byte("0|1|1000|hive|1000"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "auction_start", []byte("0|1|1000|hive|1000"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "auction_get", []byte("0|1"), nil, "hive:someone", true, uint(100_000_000), "hive:someone|1000|hive|1000||")
+
+	// the edition is locked while the auction is open
+	CallContract(t, ct, "nft_transfer", []byte("0|1|hive:someoneelse_0"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_burn", []byte("0|1"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "list_create", []byte("0|1|1000|hive"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_transfer", []byte("0|2|hive:someoneelse_0"), nil, "hive:someone", true, uint(100_000_000), "")
+
+	// bids below reserve, by the seller or without intent fail; settling early fails
+	CallContract(t, ct, "auction_bid", []byte("0|1|999|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "auction_bid", []byte("0|1|1000|hive:someone_0"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "auction_bid", []byte("0|1|1000|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "auction_settle", []byte("0|1"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+}
+
+func TestAuctionsSettle(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:third", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false|5|1000|hive:artist|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	ct.Deposit("hive:someoneelse", 5_000, ledgerDb.AssetHive)
+	ct.Deposit("hive:third", 5_000, ledgerDb.AssetHive)
+
+	CallContractAt(t, ct, 10, "auction_start", []byte("0|1|1000|hive|100"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContractAt(t, ct, 20, "auction_bid", []byte("0|1|1000|hive:someoneelse_0"), TransferIntent("1.000", "hive"), "hive:someoneelse", true, uint(1_000_000_000), "")
+	// outbidding refunds the previous bidder right away
+	CallContractAt(t, ct, 30, "auction_bid", []byte("0|1|2000|hive:third_0"), TransferIntent("2.000", "hive"), "hive:third", true, uint(1_000_000_000), "")
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHive, 5_000)
+	AssertBalance(t, ct, "hive:third", ledgerDb.AssetHive, 3_000)
+	CallContractAt(t, ct, 40, "auction_get", []byte("0|1"), nil, "hive:someone", true, uint(100_000_000), "hive:someone|1000|hive|100|hive:third|2000")
+
+	CallContractAt(t, ct, 99, "auction_settle", []byte("0|1"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContractAt(t, ct, 100, "auction_settle", []byte("0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0|1"), nil, "hive:third", true, uint(100_000_000), "true")
+	CallGetterEmpty(t, ct, "auction_get", []byte("0|1"))
+	AssertBalance(t, ct, "hive:artist", ledgerDb.AssetHive, 200)
+	AssertBalance(t, ct, "hive:someone", ledgerDb.AssetHive, 1_800)
+
+	// the winning bid is binding: giving the target collection away after
+	// bidding still pays the seller and delivers the token to that collection
+	CallContractAt(t, ct, 110, "auction_start", []byte("0|2|1000|hive|200"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContractAt(t, ct, 120, "auction_bid", []byte("0|2|1000|hive:someoneelse_0"), TransferIntent("1.000", "hive"), "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContractAt(t, ct, 200, "col_transfer", []byte("hive:someoneelse_0|hive:third"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContractAt(t, ct, 200, "auction_settle", []byte("0|2"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0|2"), nil, "hive:third", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_ownerColOf", []byte("0|2"), nil, "hive:someone", true, uint(100_000_000), "hive:someoneelse_0")
+	CallGetterEmpty(t, ct, "auction_get", []byte("0|2"))
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHive, 4_000)
+	AssertBalance(t, ct, "hive:artist", ledgerDb.AssetHive, 300)
+	AssertBalance(t, ct, "hive:someone", ledgerDb.AssetHive, 2_700)
+}
+
+// // offer tests
+func TestOffers(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|5|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// offers need an intent covering the amount, a future expiry and an owned target collection
+	CallContract(t, ct, "offer_make", []byte("0|1|1000|hive|0|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "offer_make", []byte("0|1|1000|hive|100000|hive:someoneelse_0"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "offer_make", []byte("0|1|1000|hive|100000|hive:someone_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "offer_make", []byte("0|1|1000|hive|100000|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+
+	CallContract(t, ct, "offers_for", []byte("0|1||"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "offer_cancel", []byte("0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "offer_accept", []byte("0"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
+func TestOffersAccept(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false|5|500|hive:artist|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	ct.Deposit("hive:someoneelse", 5_000, ledgerDb.AssetHive)
+
+	// the amount is escrowed until the offer is accepted or cancelled
+	CallContractAt(t, ct, 10, "offer_make", []byte("0|1|1000|hive|1000|hive:someoneelse_0"), TransferIntent("1.000", "hive"), "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContractAt(t, ct, 10, "offer_make", []byte("0|2|2000|hive|1000|hive:someoneelse_0"), TransferIntent("2.000", "hive"), "hive:someoneelse", true, uint(1_000_000_000), "")
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHive, 2_000)
+	CallContractAt(t, ct, 20, "offers_for", []byte("0|1||"), nil, "hive:someone", true, uint(100_000_000), "0|hive:someoneelse|1000|hive|1000")
+
+	CallContractAt(t, ct, 20, "offer_accept", []byte("0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContractAt(t, ct, 20, "offer_accept", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0|1"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	CallGetterEmpty(t, ct, "offers_for", []byte("0|1||"))
+	AssertBalance(t, ct, "hive:artist", ledgerDb.AssetHive, 50)
+	AssertBalance(t, ct, "hive:someone", ledgerDb.AssetHive, 950)
+
+	// cancelling refunds the escrow
+	CallContractAt(t, ct, 30, "offer_cancel", []byte("1"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContractAt(t, ct, 30, "offer_cancel", []byte("1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallGetterEmpty(t, ct, "offers_for", []byte("0|2||"))
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHive, 4_000)
+}
+
+// // collection offer tests
+func TestCollectionOffers(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|5|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// the collection must exist, the quantity be positive and the escrow covered by an intent
+	CallContract(t, ct, "offer_makeCollection", []byte("hive:nobody_0|50|hbd|2|100000|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "offer_makeCollection", []byte("hive:someone_0|50|hbd|0|100000|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "offer_makeCollection", []byte("hive:someone_0|50|hbd|2|100000|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+
+	CallContract(t, ct, "col_offers", []byte("hive:someone_0||"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "offer_fill", []byte("0|0|1"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
+func TestCollectionOffersFill(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false|5|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	ct.Deposit("hive:someoneelse", 1_000, ledgerDb.AssetHbd)
+
+	// 2 items at 0.050 HBD each escrow 0.100 HBD
+	CallContractAt(t, ct, 10, "offer_makeCollection", []byte("hive:someone_0|50|hbd|2|1000|hive:someoneelse_0"), TransferIntent("0.100", "hbd"), "hive:someoneelse", true, uint(1_000_000_000), "")
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHbd, 900)
+	CallContract(t, ct, "col_offers", []byte("hive:someone_0||"), nil, "hive:someone", true, uint(100_000_000), "0|hive:someoneelse|50|hbd|1000|2")
+
+	CallContractAt(t, ct, 20, "offer_fill", []byte("0|0|1"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_offers", []byte("hive:someone_0||"), nil, "hive:someone", true, uint(100_000_000), "0|hive:someoneelse|50|hbd|1000|1")
+	CallContractAt(t, ct, 20, "offer_fill", []byte("0|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0|1"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_isOwner", []byte("0|2"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	// the offer closes once all items are filled
+	CallGetterEmpty(t, ct, "col_offers", []byte("hive:someone_0||"))
+	CallContractAt(t, ct, 20, "offer_fill", []byte("0|0|3"), nil, "hive:someone", false, uint(100_000_000), "")
+	AssertBalance(t, ct, "hive:someone", ledgerDb.AssetHbd, 100)
+
+	// cancelling refunds the items not filled yet
+	CallContractAt(t, ct, 30, "offer_makeCollection", []byte("hive:someone_0|100|hbd|3|1000|hive:someoneelse_0"), TransferIntent("0.300", "hbd"), "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContractAt(t, ct, 40, "offer_fill", []byte("1|0|3"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContractAt(t, ct, 50, "offer_cancel", []byte("1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallGetterEmpty(t, ct, "col_offers", []byte("hive:someone_0||"))
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHbd, 800)
+	AssertBalance(t, ct, "hive:someone", ledgerDb.AssetHbd, 200)
+}
+
+// // drop tests
+func TestDrops(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someoneelse", true, uint(1_000_000_000), "")
+
+	// only the collection owner can open a drop, with a falling price
+	CallContract(t, ct, "col_setDrop", []byte("hive:someone_0|10000|1000|0|1000|hive|1|name|description|false|||test=123"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setDrop", []byte("hive:someone_0|1000|10000|0|1000|hive|1|name|description|false|||test=123"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setDrop", []byte("hive:someone_0|10000|1000|0|1000|hive|1|name|description|false|||test=123"), nil, "hive:someone", true, uint(100_000_000), "")
+
+	// minting needs an intent covering the current price and an owned target collection
+	CallContract(t, ct, "drop_mint", []byte("hive:someone_0|hive:someone_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "drop_mint", []byte("hive:someone_0|hive:someoneelse_0"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+
+	CallContract(t, ct, "col_setDrop", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "drop_get", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "col_setDrop", []byte("hive:someone_0"), nil, "hive:someone", false, uint(100_000_000), "")
+}
+
+func TestDropsMint(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_setMaxSupply", []byte("hive:someone_0|2|"), nil, "hive:someone", true, uint(100_000_000), "")
+	for _, buyer := range []string{"hive:someoneelse", "hive:third", "hive:fourth"} {
+		CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, buyer, true, uint(1_000_000_000), "")
+		ct.Deposit(buyer, 5_000, ledgerDb.AssetHive)
+	}
+	// price falls from 2.000 to 1.000 HIVE over blocks 0-100, one mint per wallet
+	CallContract(t, ct, "col_setDrop", []byte("hive:someone_0|2000|1000|0|100|hive|1|name|description|false|||test=123"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContractAt(t, ct, 50, "drop_get", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "2000|1000|0|100|hive|1|1500")
+
+	CallContractAt(t, ct, 50, "drop_mint", []byte("hive:someone_0|hive:someoneelse_0"), TransferIntent("1.499", "hive"), "hive:someoneelse", false, uint(100_000_000), "")
+	CallContractAt(t, ct, 50, "drop_mint", []byte("hive:someone_0|hive:someoneelse_0"), TransferIntent("1.500", "hive"), "hive:someoneelse", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("0"), nil, "hive:someoneelse", true, uint(100_000_000), "true")
+	// the wallet limit is reached
+	CallContractAt(t, ct, 60, "drop_mint", []byte("hive:someone_0|hive:someoneelse_0"), TransferIntent("2.000", "hive"), "hive:someoneelse", false, uint(100_000_000), "")
+
+	CallContractAt(t, ct, 150, "drop_mint", []byte("hive:someone_0|hive:third_0"), TransferIntent("1.000", "hive"), "hive:third", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_isOwner", []byte("1"), nil, "hive:third", true, uint(100_000_000), "true")
+	// the supply cap of 2 is reached
+	CallContractAt(t, ct, 160, "drop_mint", []byte("hive:someone_0|hive:fourth_0"), TransferIntent("1.000", "hive"), "hive:fourth", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_supply", []byte("hive:someone_0"), nil, "hive:someone", true, uint(100_000_000), "2|2|2|0")
+
+	AssertBalance(t, ct, "hive:someone", ledgerDb.AssetHive, 2_500)
+	AssertBalance(t, ct, "hive:someoneelse", ledgerDb.AssetHive, 3_500)
+	AssertBalance(t, ct, "hive:third", ledgerDb.AssetHive, 4_000)
+	AssertBalance(t, ct, "hive:fourth", ledgerDb.AssetHive, 5_000)
+}
@@ -0,0 +1,32 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+// metadata updates
+func TestMetadataUpdates(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+
+	// not the creator (should fail)
+	CallContract(t, ct, "nft_setMeta", []byte("0|test=456"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_setMeta", []byte("0|test=456"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_meta", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "test=456")
+
+	// designated updater
+	CallContract(t, ct, "nft_setMetaUpdater", []byte("0|hive:game"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_setMetaUpdater", []byte("0|game"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_setMetaUpdater", []byte("0|hive:game"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_setMeta", []byte("0|level=2"), nil, "hive:game", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_meta", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "level=2")
+
+	// freeze is one-way
+	CallContract(t, ct, "nft_isMetaFrozen", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "false")
+	CallContract(t, ct, "nft_freezeMeta", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "")
+	CallContract(t, ct, "nft_isMetaFrozen", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "true")
+	CallContract(t, ct, "nft_setMeta", []byte("0|level=3"), nil, "hive:game", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_setMeta", []byte("0|level=3"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_meta", []byte("0"), nil, "hive:someone", true, uint(100_000_000), "level=2")
+}
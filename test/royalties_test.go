@@ -0,0 +1,53 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+// // royalty tests
+func TestRoyaltyInfo(t *testing.T) {
+	ct := SetupContractTest()
+	// collection without and with default royalty (2.5% to collection owner)
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_create", []byte("collectionB|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_1|250|"), nil, "hive:someone", true, uint(100_000_000), "")
+	// royalty above maximum or not the owner (should fail)
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_0|6000|"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_0|250|"), nil, "hive:someoneelse", false, uint(100_000_000), "")
+
+	// nft without royalty
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	// nft with explicit 5% royalty
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false||500|hive:artist|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	// nft with collection default royalty
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_1|name|description|false|10|test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	// bps without valid recipient (should fail)
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false||500|artist|test=123"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false||500|hive:art\"ist|test=123"), nil, "hive:someone", false, uint(100_000_000), "")
+
+	CallContract(t, ct, "nft_royaltyInfo", []byte("0|1000"), nil, "hive:someone", true, uint(100_000_000), "|0")
+	CallContract(t, ct, "nft_royaltyInfo", []byte("1|1000"), nil, "hive:someone", true, uint(100_000_000), "hive:artist|50")
+	CallContract(t, ct, "nft_royaltyInfo", []byte("2|1000"), nil, "hive:someone", true, uint(100_000_000), "hive:someone|25")
+	CallContract(t, ct, "nft_meta", []byte("1"), nil, "hive:someone", true, uint(100_000_000), "test=123")
+
+	// metadata containing '|' is never read as royalty fields
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||a=1|b=2|c=3"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_meta", []byte("3"), nil, "hive:someone", true, uint(100_000_000), "a=1|b=2|c=3")
+	CallContract(t, ct, "nft_royaltyInfo", []byte("3|1000"), nil, "hive:someone", true, uint(100_000_000), "|0")
+}
+
+func TestRoyaltySplits(t *testing.T) {
+	ct := SetupContractTest()
+	// collection default split: 5% shared by two artists and a platform
+	CallContract(t, ct, "col_create", []byte("collectionA|my description|img=testurl"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_0|500|hive:artist1:200,hive:artist2:200,hive:platform:100"), nil, "hive:someone", true, uint(100_000_000), "")
+	// shares not adding up to the total (should fail)
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_0|500|hive:artist1:200,hive:artist2:200"), nil, "hive:someone", false, uint(100_000_000), "")
+	// every share needs a valid recipient (should fail)
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_0|500|hive:artist1:400,artist2:100"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "col_setRoyalty", []byte("hive:someone_0|500|hive:artist1:400,hive:art\"ist2:100"), nil, "hive:someone", false, uint(100_000_000), "")
+	CallContract(t, ct, "nft_mintRoyalty", []byte("hive:someone_0|name|description|false||500|hive:artist1:300,hive:artist2:300|test=123"), nil, "hive:someone", false, uint(100_000_000), "")
+
+	CallContract(t, ct, "nft_mint", []byte("hive:someone_0|name|description|false||test=123"), nil, "hive:someone", true, uint(1_000_000_000), "")
+	CallContract(t, ct, "nft_royaltyInfo", []byte("0|1000"), nil, "hive:someone", true, uint(100_000_000), "hive:artist1|20,hive:artist2|20,hive:platform|10")
+}